@@ -1,13 +1,27 @@
 package qarnot
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 )
 
 type Bucket struct {
@@ -24,29 +38,52 @@ type BucketObject struct {
 
 // Create a new bucket
 func (c *Client) CreateBucket(bucketName string) error {
+	return c.CreateBucketWithContext(context.Background(), bucketName)
+}
+
+// Same as `CreateBucket`, but accepting a context to control cancellation and deadlines
+func (c *Client) CreateBucketWithContext(ctx context.Context, bucketName string) error {
 	_, err := c.s3.CreateBucket(
-		context.TODO(),
+		ctx,
 		&s3.CreateBucketInput{
 			Bucket: aws.String(bucketName),
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("could not create bucket (%v) due to the following error : %v", bucketName, err)
+		return fmt.Errorf("could not create bucket (%v) due to the following error : %w", bucketName, err)
 	}
 
 	return nil
 }
 
+// Options for deleting a bucket
+type DeleteBucketOptions struct {
+	// Empty the bucket first using `EmptyBucket`, as only empty buckets can be deleted
+	Force bool
+}
+
 // Delete a bucket
-func (c *Client) DeleteBucket(bucketName string) error {
+func (c *Client) DeleteBucket(bucketName string, options ...DeleteBucketOptions) error {
+	return c.DeleteBucketWithContext(context.Background(), bucketName, options...)
+}
+
+// Same as `DeleteBucket`, but accepting a context to control cancellation and deadlines
+func (c *Client) DeleteBucketWithContext(ctx context.Context, bucketName string, options ...DeleteBucketOptions) error {
+	if len(options) > 0 && options[0].Force {
+		_, err := c.EmptyBucketWithContext(ctx, bucketName)
+		if err != nil {
+			return fmt.Errorf("could not delete bucket (%v) due to the following error : %w", bucketName, err)
+		}
+	}
+
 	_, err := c.s3.DeleteBucket(
-		context.TODO(),
+		ctx,
 		&s3.DeleteBucketInput{
 			Bucket: aws.String(bucketName),
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("could not delete bucket (%v) due to the following error : %v", bucketName, err)
+		return fmt.Errorf("could not delete bucket (%v) due to the following error : %w", bucketName, err)
 	}
 
 	return nil
@@ -54,12 +91,17 @@ func (c *Client) DeleteBucket(bucketName string) error {
 
 // List the buckets of the authenticated user
 func (c *Client) ListBuckets() (*[]Bucket, error) {
+	return c.ListBucketsWithContext(context.Background())
+}
+
+// Same as `ListBuckets`, but accepting a context to control cancellation and deadlines
+func (c *Client) ListBucketsWithContext(ctx context.Context) (*[]Bucket, error) {
 	bucketsRaw, err := c.s3.ListBuckets(
-		context.TODO(),
+		ctx,
 		&s3.ListBucketsInput{},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("could not list buckets due to the following error : %v", err)
+		return nil, fmt.Errorf("could not list buckets due to the following error : %w", err)
 	}
 
 	var buckets []Bucket
@@ -69,31 +111,179 @@ func (c *Client) ListBuckets() (*[]Bucket, error) {
 	return &buckets, nil
 }
 
-// List objects inside of a bucket
+// List objects inside of a bucket, going through every page
 func (c *Client) ListObjects(bucketName string) (*[]BucketObject, error) {
-	objectsRaw, err := c.s3.ListObjectsV2(
-		context.TODO(),
-		&s3.ListObjectsV2Input{
+	return c.ListObjectsWithContext(context.Background(), bucketName)
+}
+
+// Same as `ListObjects`, but accepting a context to control cancellation and deadlines
+func (c *Client) ListObjectsWithContext(ctx context.Context, bucketName string) (*[]BucketObject, error) {
+	listing, err := c.ListBucketObjectsWithContext(ctx, bucketName, ListObjectsOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &listing.Objects, nil
+}
+
+// Options for listing the objects of a bucket
+type ListObjectsOptions struct {
+	// Only list the objects whose key starts with this prefix
+	Prefix string
+	// Group the keys containing the delimiter after the prefix into common prefixes, such as "/" to list
+	// a single level of "directories"
+	Delimiter string
+	// Only list the keys after this one, in alphabetical order
+	StartAfter string
+	// Maximum number of keys returned per request, 1000 when 0
+	MaxKeys int32
+}
+
+// Represent the objects of a bucket, as returned by a listing
+type ObjectListing struct {
+	Objects []BucketObject
+	// Prefixes grouping keys when a delimiter was given, including the delimiter
+	CommonPrefixes []string
+}
+
+// Pager over the objects of a bucket, fetching the pages one at a time, only when asked to
+//
+//	pager := client.PaginateObjects("my-bucket", qarnot.ListObjectsOptions{Prefix: "frames/"})
+//	for pager.HasMorePages() {
+//		page, err := pager.NextPage()
+//		...
+//	}
+type ObjectPager struct {
+	bucketName string
+	paginator  *s3.ListObjectsV2Paginator
+}
+
+// Will paginate over the objects of a bucket
+// No request is sent until the first page is asked for
+func (c *Client) PaginateObjects(bucketName string, options ListObjectsOptions) *ObjectPager {
+	input := &s3.ListObjectsV2Input{Bucket: &bucketName}
+	if options.Prefix != "" {
+		input.Prefix = &options.Prefix
+	}
+	if options.Delimiter != "" {
+		input.Delimiter = &options.Delimiter
+	}
+	if options.StartAfter != "" {
+		input.StartAfter = &options.StartAfter
+	}
+	if options.MaxKeys > 0 {
+		input.MaxKeys = &options.MaxKeys
+	}
+
+	return &ObjectPager{bucketName: bucketName, paginator: s3.NewListObjectsV2Paginator(c.s3, input)}
+}
+
+// Will return true until the last page was fetched
+func (p *ObjectPager) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+// Will fetch the next page
+func (p *ObjectPager) NextPage() (ObjectListing, error) {
+	return p.NextPageWithContext(context.Background())
+}
+
+// Same as `NextPage`, but accepting a context to control cancellation and deadlines
+func (p *ObjectPager) NextPageWithContext(ctx context.Context) (ObjectListing, error) {
+	page, err := p.paginator.NextPage(ctx)
+	if err != nil {
+		return ObjectListing{}, fmt.Errorf("could not list objects in bucket (%v) due to the following error : %w", p.bucketName, err)
+	}
+
+	listing := ObjectListing{Objects: []BucketObject{}, CommonPrefixes: []string{}}
+	for _, obj := range page.Contents {
+		listing.Objects = append(
+			listing.Objects,
+			BucketObject{
+				Name:         aws.ToString(obj.Key),
+				LastModified: aws.ToTime(obj.LastModified),
+				Size:         aws.ToInt64(obj.Size),
+				ETag:         aws.ToString(obj.ETag),
+			})
+	}
+	for _, commonPrefix := range page.CommonPrefixes {
+		listing.CommonPrefixes = append(listing.CommonPrefixes, aws.ToString(commonPrefix.Prefix))
+	}
+
+	return listing, nil
+}
+
+// List the objects of a bucket, going through every page
+func (c *Client) ListBucketObjects(bucketName string, options ListObjectsOptions) (ObjectListing, error) {
+	return c.ListBucketObjectsWithContext(context.Background(), bucketName, options)
+}
+
+// Same as `ListBucketObjects`, but accepting a context to control cancellation and deadlines
+func (c *Client) ListBucketObjectsWithContext(ctx context.Context, bucketName string, options ListObjectsOptions) (ObjectListing, error) {
+	listing := ObjectListing{Objects: []BucketObject{}, CommonPrefixes: []string{}}
+
+	pager := c.PaginateObjects(bucketName, options)
+	for pager.HasMorePages() {
+		page, err := pager.NextPageWithContext(ctx)
+		if err != nil {
+			return listing, err
+		}
+		listing.Objects = append(listing.Objects, page.Objects...)
+		listing.CommonPrefixes = append(listing.CommonPrefixes, page.CommonPrefixes...)
+	}
+
+	return listing, nil
+}
+
+// Download an object to a local file, checking its size and, when possible, its MD5 against its ETag
+// The object is first written to a temporary file next to the local path, which is only replaced once complete
+func (c *Client) downloadObject(ctx context.Context, bucketName string, key string, localPath string) (int64, error) {
+	object, err := c.s3.GetObject(
+		ctx,
+		&s3.GetObjectInput{
 			Bucket: &bucketName,
+			Key:    &key,
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("could not list objects in bucket (%v) due to the following error : %v", bucketName, err)
+		return 0, err
 	}
+	defer object.Body.Close()
 
-	var bucketObjects []BucketObject
-	for _, obj := range objectsRaw.Contents {
-		bucketObjects = append(
-			bucketObjects,
-			BucketObject{
-				Name:         *obj.Key,
-				LastModified: *obj.LastModified,
-				Size:         *obj.Size,
-				ETag:         *obj.ETag,
-			})
+	err = os.MkdirAll(filepath.Dir(localPath), 0o755)
+	if err != nil {
+		return 0, err
 	}
 
-	return &bucketObjects, nil
+	file, err := os.CreateTemp(filepath.Dir(localPath), "."+filepath.Base(localPath)+".*.part")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(file.Name())
+
+	hash := md5.New()
+	written, err := io.Copy(io.MultiWriter(file, hash), object.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, err
+	}
+
+	if object.ContentLength != nil && written != *object.ContentLength {
+		return written, fmt.Errorf("size mismatch for %v, expected %v bytes but received %v", key, *object.ContentLength, written)
+	}
+
+	// ETags of objects uploaded in multiple parts are not the MD5 of the object, and contain a dash
+	etag := strings.Trim(aws.ToString(object.ETag), `"`)
+	if len(etag) == 32 && !strings.Contains(etag, "-") {
+		sum := hex.EncodeToString(hash.Sum(nil))
+		if !strings.EqualFold(sum, etag) {
+			return written, fmt.Errorf("checksum mismatch for %v, expected %v but computed %v", key, etag, sum)
+		}
+	}
+
+	return written, os.Rename(file.Name(), localPath)
 }
 
 // Input for uploading an object into a bucket
@@ -105,21 +295,105 @@ type ObjectToUpload struct {
 
 // Upload objects in bucket
 func (c *Client) UploadObject(object *ObjectToUpload) error {
+	return c.UploadObjectWithContext(context.Background(), object)
+}
+
+// Same as `UploadObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) UploadObjectWithContext(ctx context.Context, object *ObjectToUpload) error {
 	body, err := os.Open(object.LocalPath)
 	if err != nil {
-		return fmt.Errorf("could not upload object to bucket due to the following error : %v", err)
+		return fmt.Errorf("could not upload object to bucket due to the following error : %w", err)
 	}
+	defer body.Close()
 
-	_, err = c.s3.PutObject(
-		context.TODO(),
-		&s3.PutObjectInput{
-			Bucket: &object.Bucket,
-			Key:    &object.Key,
-			Body:   body,
-		},
-	)
+	return c.PutObjectWithContext(ctx, object.Bucket, object.Key, body, PutOptions{})
+}
+
+// Guess the content type of an object from the extension of its key, nil when unknown
+func contentTypeOf(key string) *string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return &contentType
+	}
+	return nil
+}
+
+// Options for putting an object into a bucket
+type PutOptions struct {
+	// Content type of the object, guessed from the extension of the key when empty
+	ContentType string
+	// User defined metadata, stored along the object
+	Metadata map[string]string
+	// Value of the Cache-Control header returned when getting the object
+	CacheControl string
+	// Base64 encoded MD5 of the body, checked by the storage
+	// Computed when empty, unless the body cannot be seeked and `ContentLength` is set
+	ContentMD5 string
+	// Size of the body, only used when it cannot be seeked (such as a pipe)
+	// When 0 for such a body, it is read in memory first to know its size
+	ContentLength int64
+}
+
+// Put an object in bucket, reading its content from `body`
+func (c *Client) PutObject(bucketName string, key string, body io.Reader, options PutOptions) error {
+	return c.PutObjectWithContext(context.Background(), bucketName, key, body, options)
+}
+
+// Same as `PutObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) PutObjectWithContext(ctx context.Context, bucketName string, key string, body io.Reader, options PutOptions) error {
+	input := &s3.PutObjectInput{
+		Bucket:   &bucketName,
+		Key:      &key,
+		Metadata: options.Metadata,
+	}
+	if options.CacheControl != "" {
+		input.CacheControl = &options.CacheControl
+	}
+
+	input.ContentType = contentTypeOf(key)
+	if options.ContentType != "" {
+		input.ContentType = &options.ContentType
+	}
+
+	var s3Options []func(*s3.Options)
+	seeker, seekable := body.(io.ReadSeeker)
+	if !seekable && options.ContentLength > 0 {
+		// The body can only be read once, so its SHA256 cannot be computed to sign the request
+		input.ContentLength = &options.ContentLength
+		s3Options = append(s3Options, s3.WithAPIOptions(v4.SwapComputePayloadSHA256ForUnsignedPayloadMiddleware))
+	} else if !seekable {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("could not upload object to bucket due to the following error : %w", err)
+		}
+		seeker = bytes.NewReader(data)
+	}
+
+	if seeker != nil {
+		body = seeker
+		if options.ContentMD5 == "" {
+			start, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return fmt.Errorf("could not upload object to bucket due to the following error : %w", err)
+			}
+			hash := md5.New()
+			_, err = io.Copy(hash, seeker)
+			if err == nil {
+				_, err = seeker.Seek(start, io.SeekStart)
+			}
+			if err != nil {
+				return fmt.Errorf("could not upload object to bucket due to the following error : %w", err)
+			}
+			options.ContentMD5 = base64.StdEncoding.EncodeToString(hash.Sum(nil))
+		}
+	}
+	if options.ContentMD5 != "" {
+		input.ContentMD5 = &options.ContentMD5
+	}
+	input.Body = body
+
+	_, err := c.s3.PutObject(ctx, input, s3Options...)
 	if err != nil {
-		return fmt.Errorf("could not upload object to bucket due to the following error : %v", err)
+		return fmt.Errorf("could not upload object to bucket due to the following error : %w", err)
 	}
 
 	return nil
@@ -133,20 +407,217 @@ type ObjectToDelete struct {
 
 // Delete object in bucket
 func (c *Client) DeleteObject(object ObjectToDelete) error {
+	return c.DeleteObjectWithContext(context.Background(), object)
+}
+
+// Same as `DeleteObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) DeleteObjectWithContext(ctx context.Context, object ObjectToDelete) error {
 	_, err := c.s3.DeleteObject(
-		context.TODO(),
+		ctx,
 		&s3.DeleteObjectInput{
 			Bucket: &object.Bucket,
 			Key:    &object.Key,
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("could not delete object in bucket due to the following error : %v", err)
+		return fmt.Errorf("could not delete object in bucket due to the following error : %w", err)
 	}
 
 	return nil
 }
 
+// Maximum number of keys S3 accepts in a single batch delete
+const deleteObjectsBatchSize = 1000
+
+// Represent the failure to delete a key during a batch delete
+type DeleteObjectError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+// Error returned when some keys of a batch delete could not be deleted
+// The other keys were deleted
+type DeleteObjectsError struct {
+	Errors []DeleteObjectError
+}
+
+func (e *DeleteObjectsError) Error() string {
+	failures := make([]string, len(e.Errors))
+	for i, failure := range e.Errors {
+		failures[i] = fmt.Sprintf("%v (%v: %v)", failure.Key, failure.Code, failure.Message)
+	}
+	return fmt.Sprintf("could not delete %v objects : %v", len(e.Errors), strings.Join(failures, ", "))
+}
+
+// Delete objects in bucket, using batches of 1000 keys
+// Every batch is sent even if some keys could not be deleted, in which case the returned error is a
+// `*DeleteObjectsError` listing them
+// Returns the number of deleted objects
+func (c *Client) DeleteObjects(bucketName string, keys []string) (int, error) {
+	return c.DeleteObjectsWithContext(context.Background(), bucketName, keys)
+}
+
+// Same as `DeleteObjects`, but accepting a context to control cancellation and deadlines
+func (c *Client) DeleteObjectsWithContext(ctx context.Context, bucketName string, keys []string) (int, error) {
+	deleted := 0
+	failed := &DeleteObjectsError{}
+	for start := 0; start < len(keys); start += deleteObjectsBatchSize {
+		batch := keys[start:min(start+deleteObjectsBatchSize, len(keys))]
+
+		identifiers := make([]types.ObjectIdentifier, len(batch))
+		for i := range batch {
+			identifiers[i] = types.ObjectIdentifier{Key: &batch[i]}
+		}
+
+		output, err := c.s3.DeleteObjects(
+			ctx,
+			&s3.DeleteObjectsInput{
+				Bucket: &bucketName,
+				Delete: &types.Delete{Objects: identifiers, Quiet: aws.Bool(true)},
+			},
+		)
+		if ctx.Err() != nil {
+			return deleted, fmt.Errorf("could not delete objects in bucket due to the following error : %w", ctx.Err())
+		}
+		if err != nil {
+			for _, key := range batch {
+				failed.Errors = append(failed.Errors, DeleteObjectError{Key: key, Message: err.Error()})
+			}
+			continue
+		}
+
+		for _, failure := range output.Errors {
+			failed.Errors = append(failed.Errors, DeleteObjectError{
+				Key:     aws.ToString(failure.Key),
+				Code:    aws.ToString(failure.Code),
+				Message: aws.ToString(failure.Message),
+			})
+		}
+		deleted += len(batch) - len(output.Errors)
+	}
+
+	if len(failed.Errors) > 0 {
+		return deleted, failed
+	}
+	return deleted, nil
+}
+
+// Delete every object in bucket whose key starts with the prefix
+// Like `DeleteObjects`, keys which could not be deleted do not stop the deletion of the other ones
+// Returns the number of deleted objects
+func (c *Client) DeletePrefix(bucketName string, prefix string) (int, error) {
+	return c.DeletePrefixWithContext(context.Background(), bucketName, prefix)
+}
+
+// Same as `DeletePrefix`, but accepting a context to control cancellation and deadlines
+func (c *Client) DeletePrefixWithContext(ctx context.Context, bucketName string, prefix string) (int, error) {
+	deleted := 0
+	failed := &DeleteObjectsError{}
+
+	pager := c.PaginateObjects(bucketName, ListObjectsOptions{Prefix: prefix, MaxKeys: deleteObjectsBatchSize})
+	for pager.HasMorePages() {
+		page, err := pager.NextPageWithContext(ctx)
+		if err != nil {
+			return deleted, err
+		}
+
+		keys := make([]string, len(page.Objects))
+		for i, object := range page.Objects {
+			keys[i] = object.Name
+		}
+
+		count, err := c.DeleteObjectsWithContext(ctx, bucketName, keys)
+		deleted += count
+
+		var batchErr *DeleteObjectsError
+		if errors.As(err, &batchErr) {
+			failed.Errors = append(failed.Errors, batchErr.Errors...)
+		} else if err != nil {
+			return deleted, err
+		}
+	}
+
+	if len(failed.Errors) > 0 {
+		return deleted, failed
+	}
+	return deleted, nil
+}
+
+// Delete every object in bucket, and abort its unfinished multipart uploads, so that it can be deleted
+// Like `DeleteObjects`, objects and uploads which could not be deleted do not stop the deletion of the
+// other ones, and are listed by the returned `*DeleteObjectsError`
+// Returns the number of deleted objects
+func (c *Client) EmptyBucket(bucketName string) (int, error) {
+	return c.EmptyBucketWithContext(context.Background(), bucketName)
+}
+
+// Same as `EmptyBucket`, but accepting a context to control cancellation and deadlines
+func (c *Client) EmptyBucketWithContext(ctx context.Context, bucketName string) (int, error) {
+	failed := &DeleteObjectsError{}
+
+	deleted, err := c.DeletePrefixWithContext(ctx, bucketName, "")
+	var batchErr *DeleteObjectsError
+	if errors.As(err, &batchErr) {
+		failed.Errors = append(failed.Errors, batchErr.Errors...)
+	} else if err != nil {
+		return deleted, err
+	}
+
+	aborted, err := c.abortMultipartUploads(ctx, bucketName)
+	if err != nil {
+		return deleted, err
+	}
+	failed.Errors = append(failed.Errors, aborted...)
+
+	if len(failed.Errors) > 0 {
+		return deleted, failed
+	}
+	return deleted, nil
+}
+
+// Will abort every unfinished multipart upload of a bucket, such as the ones kept by a `TransferManager`
+// to be resumed, as their parts prevent the bucket from being deleted
+// Returns the uploads which could not be aborted
+func (c *Client) abortMultipartUploads(ctx context.Context, bucketName string) ([]DeleteObjectError, error) {
+	failed := []DeleteObjectError{}
+
+	input := &s3.ListMultipartUploadsInput{Bucket: &bucketName}
+	for {
+		output, err := c.s3.ListMultipartUploads(ctx, input)
+		if err != nil {
+			return failed, fmt.Errorf("could not list multipart uploads in bucket (%v) due to the following error : %w", bucketName, err)
+		}
+
+		for _, upload := range output.Uploads {
+			_, err := c.s3.AbortMultipartUpload(
+				ctx,
+				&s3.AbortMultipartUploadInput{
+					Bucket:   &bucketName,
+					Key:      upload.Key,
+					UploadId: upload.UploadId,
+				},
+			)
+			if ctx.Err() != nil {
+				return failed, ctx.Err()
+			}
+			if err != nil {
+				failure := DeleteObjectError{Key: aws.ToString(upload.Key), Message: err.Error()}
+				var apiErr smithy.APIError
+				if errors.As(err, &apiErr) {
+					failure.Code, failure.Message = apiErr.ErrorCode(), apiErr.ErrorMessage()
+				}
+				failed = append(failed, failure)
+			}
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			return failed, nil
+		}
+		input.KeyMarker, input.UploadIdMarker = output.NextKeyMarker, output.NextUploadIdMarker
+	}
+}
+
 // Input for getting object head in bucket
 type ObjectToGetHead struct {
 	Bucket string
@@ -155,36 +626,208 @@ type ObjectToGetHead struct {
 
 // Represent object head
 type ObjectHead struct {
-	PartsCount    int32
-	ETag          string
-	ContentLength int64
-	ContentType   string
+	PartsCount      int32
+	ETag            string
+	ContentLength   int64
+	ContentType     string
+	LastModified    time.Time
+	CacheControl    string
+	ContentEncoding string
+	// User metadata of the object, with lower case keys and without the "x-amz-meta-" prefix
+	Metadata map[string]string
+	// Base64 encoded checksums of the object, only set for the algorithm it was uploaded with, if any
+	ChecksumCRC32  string
+	ChecksumCRC32C string
+	ChecksumSHA1   string
+	ChecksumSHA256 string
 }
 
 // Get object head from bucket
 func (c *Client) GetObjectHead(object ObjectToGetHead) (*ObjectHead, error) {
+	return c.GetObjectHeadWithContext(context.Background(), object)
+}
+
+// Same as `GetObjectHead`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetObjectHeadWithContext(ctx context.Context, object ObjectToGetHead) (*ObjectHead, error) {
 	head, err := c.s3.HeadObject(
-		context.TODO(),
+		ctx,
 		&s3.HeadObjectInput{
-			Bucket: &object.Bucket,
-			Key:    &object.Key,
+			Bucket:       &object.Bucket,
+			Key:          &object.Key,
+			ChecksumMode: types.ChecksumModeEnabled,
 		},
 	)
 	if err != nil {
-		return &ObjectHead{}, fmt.Errorf("could not get HEAD for the object in bucket due to the following error : %v", err)
+		return &ObjectHead{}, fmt.Errorf("could not get HEAD for the object in bucket due to the following error : %w", err)
 	}
 
 	objectHead := ObjectHead{
-		PartsCount: func() int32 {
-			if head.PartsCount != nil {
-				return *head.PartsCount
-			}
-			return 0
-		}(),
-		ETag:          *head.ETag,
-		ContentLength: *head.ContentLength,
-		ContentType:   *head.ContentType,
+		PartsCount:      aws.ToInt32(head.PartsCount),
+		ETag:            aws.ToString(head.ETag),
+		ContentLength:   aws.ToInt64(head.ContentLength),
+		ContentType:     aws.ToString(head.ContentType),
+		LastModified:    aws.ToTime(head.LastModified),
+		CacheControl:    aws.ToString(head.CacheControl),
+		ContentEncoding: aws.ToString(head.ContentEncoding),
+		Metadata:        objectMetadata(head.Metadata),
+		ChecksumCRC32:   aws.ToString(head.ChecksumCRC32),
+		ChecksumCRC32C:  aws.ToString(head.ChecksumCRC32C),
+		ChecksumSHA1:    aws.ToString(head.ChecksumSHA1),
+		ChecksumSHA256:  aws.ToString(head.ChecksumSHA256),
 	}
 
 	return &objectHead, nil
 }
+
+// The S3 client leaves the metadata unset when an object has none
+func objectMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return map[string]string{}
+	}
+	return metadata
+}
+
+// Build the head of an object from the headers returned when getting it
+func objectHeadFromGetObject(object *s3.GetObjectOutput) *ObjectHead {
+	return &ObjectHead{
+		PartsCount:      aws.ToInt32(object.PartsCount),
+		ETag:            aws.ToString(object.ETag),
+		ContentLength:   aws.ToInt64(object.ContentLength),
+		ContentType:     aws.ToString(object.ContentType),
+		LastModified:    aws.ToTime(object.LastModified),
+		CacheControl:    aws.ToString(object.CacheControl),
+		ContentEncoding: aws.ToString(object.ContentEncoding),
+		Metadata:        objectMetadata(object.Metadata),
+		ChecksumCRC32:   aws.ToString(object.ChecksumCRC32),
+		ChecksumCRC32C:  aws.ToString(object.ChecksumCRC32C),
+		ChecksumSHA1:    aws.ToString(object.ChecksumSHA1),
+		ChecksumSHA256:  aws.ToString(object.ChecksumSHA256),
+	}
+}
+
+// Get an object from bucket
+// The returned body must be closed once read
+func (c *Client) GetObject(bucketName string, key string) (io.ReadCloser, *ObjectHead, error) {
+	return c.GetObjectWithContext(context.Background(), bucketName, key)
+}
+
+// Same as `GetObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetObjectWithContext(ctx context.Context, bucketName string, key string) (io.ReadCloser, *ObjectHead, error) {
+	object, err := c.s3.GetObject(
+		ctx,
+		&s3.GetObjectInput{
+			Bucket: &bucketName,
+			Key:    &key,
+		},
+	)
+	if err != nil {
+		return nil, &ObjectHead{}, fmt.Errorf("could not get object in bucket due to the following error : %w", err)
+	}
+
+	return object.Body, objectHeadFromGetObject(object), nil
+}
+
+// Get a range of bytes of an object from bucket, starting at `offset`
+// The object is read until its end when `length` is 0 or less
+// The returned body must be closed once read, and the `ContentLength` of the head is the length of the range
+func (c *Client) GetObjectRange(bucketName string, key string, offset int64, length int64) (io.ReadCloser, *ObjectHead, error) {
+	return c.GetObjectRangeWithContext(context.Background(), bucketName, key, offset, length)
+}
+
+// Same as `GetObjectRange`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetObjectRangeWithContext(ctx context.Context, bucketName string, key string, offset int64, length int64) (io.ReadCloser, *ObjectHead, error) {
+	byteRange := fmt.Sprintf("bytes=%v-", offset)
+	if length > 0 {
+		byteRange = fmt.Sprintf("bytes=%v-%v", offset, offset+length-1)
+	}
+
+	object, err := c.s3.GetObject(
+		ctx,
+		&s3.GetObjectInput{
+			Bucket: &bucketName,
+			Key:    &key,
+			Range:  &byteRange,
+		},
+	)
+	if err != nil {
+		return nil, &ObjectHead{}, fmt.Errorf("could not get object range in bucket due to the following error : %w", err)
+	}
+
+	return object.Body, objectHeadFromGetObject(object), nil
+}
+
+// Download an object from bucket to a local file
+// Missing directories are created, and the size and checksum of the object are checked once downloaded
+func (c *Client) DownloadObject(bucketName string, key string, localPath string) error {
+	return c.DownloadObjectWithContext(context.Background(), bucketName, key, localPath)
+}
+
+// Same as `DownloadObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) DownloadObjectWithContext(ctx context.Context, bucketName string, key string, localPath string) error {
+	_, err := c.downloadObject(ctx, bucketName, key, localPath)
+	if err != nil {
+		return fmt.Errorf("could not download object from bucket due to the following error : %w", err)
+	}
+
+	return nil
+}
+
+// Get the tags of an object
+func (c *Client) GetObjectTags(bucketName string, key string) (map[string]string, error) {
+	return c.GetObjectTagsWithContext(context.Background(), bucketName, key)
+}
+
+// Same as `GetObjectTags`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetObjectTagsWithContext(ctx context.Context, bucketName string, key string) (map[string]string, error) {
+	output, err := c.s3.GetObjectTagging(
+		ctx,
+		&s3.GetObjectTaggingInput{
+			Bucket: &bucketName,
+			Key:    &key,
+		},
+	)
+	if err != nil {
+		return map[string]string{}, fmt.Errorf("could not get tags of object (%v/%v) due to the following error : %w", bucketName, key, err)
+	}
+
+	tags := map[string]string{}
+	for _, tag := range output.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return tags, nil
+}
+
+// Set the tags of an object, replacing all of its existing tags
+// Objects can have up to 10 tags
+func (c *Client) PutObjectTags(bucketName string, key string, tags map[string]string) error {
+	return c.PutObjectTagsWithContext(context.Background(), bucketName, key, tags)
+}
+
+// Same as `PutObjectTags`, but accepting a context to control cancellation and deadlines
+func (c *Client) PutObjectTagsWithContext(ctx context.Context, bucketName string, key string, tags map[string]string) error {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	tagSet := []types.Tag{}
+	for _, name := range names {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(name), Value: aws.String(tags[name])})
+	}
+
+	_, err := c.s3.PutObjectTagging(
+		ctx,
+		&s3.PutObjectTaggingInput{
+			Bucket:  &bucketName,
+			Key:     &key,
+			Tagging: &types.Tagging{TagSet: tagSet},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not put tags on object (%v/%v) due to the following error : %w", bucketName, key, err)
+	}
+
+	return nil
+}
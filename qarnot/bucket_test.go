@@ -0,0 +1,410 @@
+package qarnot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestGetObject(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "simulation/output.csv", "0123456789")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	body, head, err := client.GetObject("bucket", "simulation/output.csv")
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(data) != "0123456789" {
+		t.Errorf("unexpected content : %q (%v)", data, err)
+	}
+
+	expectedHead := ObjectHead{
+		ETag:          `"781e5e245d69b566979b86e28d23f2c7"`,
+		ContentLength: 10,
+		LastModified:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Metadata:      map[string]string{},
+	}
+	if !reflect.DeepEqual(*head, expectedHead) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedHead)
+		t.Errorf("found    : %+v", *head)
+	}
+
+	_, _, err = client.GetObject("bucket", "missing")
+	var noSuchKey *types.NoSuchKey
+	if !errors.As(err, &noSuchKey) {
+		t.Errorf("err should wrap a NoSuchKey error, found : %v", err)
+	}
+}
+
+func TestGetObjectHead(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "placeholder", "")
+	storage.store("bucket", "render.png.gz", fakeObject{
+		data:            []byte("0123456789"),
+		etag:            `"781e5e245d69b566979b86e28d23f2c7"`,
+		contentType:     "image/png",
+		cacheControl:    "no-cache",
+		contentEncoding: "gzip",
+		checksumSHA256:  "hKHfmGdI5XE8iyGkqkbvR0pe2bjJpgWQA4yJ7hsvPOE=",
+		metadata:        map[string]string{"task": "6f1d5dbe"},
+	})
+	storage.store("bucket", "untyped", fakeObject{data: []byte("0123456789"), etag: `"781e5e245d69b566979b86e28d23f2c7"`})
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	testCases := []struct {
+		key      string
+		expected ObjectHead
+	}{
+		{
+			key: "render.png.gz",
+			expected: ObjectHead{
+				ETag:            `"781e5e245d69b566979b86e28d23f2c7"`,
+				ContentLength:   10,
+				ContentType:     "image/png",
+				LastModified:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				CacheControl:    "no-cache",
+				ContentEncoding: "gzip",
+				Metadata:        map[string]string{"task": "6f1d5dbe"},
+				ChecksumSHA256:  "hKHfmGdI5XE8iyGkqkbvR0pe2bjJpgWQA4yJ7hsvPOE=",
+			},
+		},
+		{
+			// Missing headers are left empty
+			key: "untyped",
+			expected: ObjectHead{
+				ETag:          `"781e5e245d69b566979b86e28d23f2c7"`,
+				ContentLength: 10,
+				LastModified:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Metadata:      map[string]string{},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		head, err := client.GetObjectHead(ObjectToGetHead{Bucket: "bucket", Key: testCase.key})
+		if err != nil {
+			t.Errorf("err should be equal to nil: %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(*head, testCase.expected) {
+			t.Error("different values.")
+			t.Errorf("expected : %+v", testCase.expected)
+			t.Errorf("found    : %+v", *head)
+		}
+	}
+}
+
+func TestGetObjectRange(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "output.csv", "0123456789")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	for _, testCase := range []struct {
+		offset   int64
+		length   int64
+		expected string
+	}{
+		{2, 3, "234"},
+		{7, 0, "789"},
+	} {
+		body, head, err := client.GetObjectRange("bucket", "output.csv", testCase.offset, testCase.length)
+		if err != nil {
+			t.Fatalf("err should be equal to nil: %v", err)
+		}
+		data, _ := io.ReadAll(body)
+		body.Close()
+
+		if string(data) != testCase.expected || head.ContentLength != int64(len(testCase.expected)) {
+			t.Error("different values.")
+			t.Errorf("expected : %v", testCase.expected)
+			t.Errorf("found    : %v (%v bytes)", string(data), head.ContentLength)
+		}
+	}
+}
+
+func TestDownloadObject(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "output.csv", "0123456789")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	localPath := filepath.Join(t.TempDir(), "nested", "output.csv")
+	err := client.DownloadObject("bucket", "output.csv", localPath)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	data, err := os.ReadFile(localPath)
+	if err != nil || string(data) != "0123456789" {
+		t.Errorf("unexpected content : %q (%v)", data, err)
+	}
+
+	err = client.DownloadObject("bucket", "missing", localPath)
+	if err == nil {
+		t.Error("err should not be equal to nil")
+	}
+	data, _ = os.ReadFile(localPath)
+	if string(data) != "0123456789" {
+		t.Errorf("a failed download should not replace the local file, found : %q", data)
+	}
+}
+
+func TestPutObject(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	err := client.PutObject("bucket", "config/render.json", strings.NewReader(`{"frames": 10}`), PutOptions{
+		Metadata:     map[string]string{"owner": "render"},
+		CacheControl: "no-cache",
+	})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	object, _ := storage.get("bucket", "config/render.json")
+	if string(object.data) != `{"frames": 10}` || object.contentType != "application/json" || object.cacheControl != "no-cache" || object.metadata["owner"] != "render" {
+		t.Errorf("unexpected object : %+v", object)
+	}
+
+	// Readers which cannot be seeked, with and without a known length
+	for _, length := range []int64{0, 7} {
+		reader, writer := io.Pipe()
+		go func() {
+			writer.Write([]byte("tarball"))
+			writer.Close()
+		}()
+
+		err = client.PutObject("bucket", "inputs.tar", reader, PutOptions{ContentType: "application/x-tar", ContentLength: length})
+		if err != nil {
+			t.Errorf("err should be equal to nil: %v", err)
+		}
+		object, _ = storage.get("bucket", "inputs.tar")
+		if string(object.data) != "tarball" || object.contentType != "application/x-tar" {
+			t.Errorf("unexpected object : %+v", object)
+		}
+	}
+
+	err = client.PutObject("bucket", "corrupted", strings.NewReader("data"), PutOptions{ContentMD5: "AAAAAAAAAAAAAAAAAAAAAA=="})
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "BadDigest" {
+		t.Errorf("err should be a BadDigest error, found : %v", err)
+	}
+
+	localPath := filepath.Join(t.TempDir(), "scene.png")
+	os.WriteFile(localPath, []byte("png"), 0o644)
+	err = client.UploadObject(&ObjectToUpload{Bucket: "bucket", LocalPath: localPath, Key: "scene.png"})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if object, _ := storage.get("bucket", "scene.png"); string(object.data) != "png" || object.contentType != "image/png" {
+		t.Errorf("unexpected object : %+v", object)
+	}
+}
+
+func TestListObjects(t *testing.T) {
+	storage := newFakeS3(t)
+	for _, key := range []string{"frames/0001.png", "frames/0002.png", "frames/0003.png", "logs/out.log", "scene.blend"} {
+		storage.put("bucket", key, key)
+	}
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	// Every page is listed, even though the storage returns 2 keys per page
+	objects, err := client.ListObjects("bucket")
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	var names []string
+	for _, object := range *objects {
+		names = append(names, object.Name)
+	}
+	expectedNames := "[frames/0001.png frames/0002.png frames/0003.png logs/out.log scene.blend]"
+	if fmt.Sprint(names) != expectedNames {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedNames)
+		t.Errorf("found    : %v", names)
+	}
+
+	for _, testCase := range []struct {
+		options  ListObjectsOptions
+		expected string
+	}{
+		{ListObjectsOptions{Delimiter: "/"}, "[scene.blend] [frames/ logs/]"},
+		{ListObjectsOptions{Prefix: "frames/", StartAfter: "frames/0001.png"}, "[frames/0002.png frames/0003.png] []"},
+	} {
+		listing, err := client.ListBucketObjects("bucket", testCase.options)
+		if err != nil {
+			t.Errorf("err should be equal to nil: %v", err)
+		}
+
+		names = []string{}
+		for _, object := range listing.Objects {
+			names = append(names, object.Name)
+		}
+		if found := fmt.Sprint(names, " ", listing.CommonPrefixes); found != testCase.expected {
+			t.Error("different values.")
+			t.Errorf("expected : %v", testCase.expected)
+			t.Errorf("found    : %v", found)
+		}
+	}
+
+	pager := client.PaginateObjects("bucket", ListObjectsOptions{Prefix: "frames/", MaxKeys: 1})
+	pages := 0
+	for pager.HasMorePages() {
+		page, err := pager.NextPage()
+		if err != nil {
+			t.Fatalf("err should be equal to nil: %v", err)
+		}
+		if len(page.Objects) != 1 {
+			t.Errorf("expected 1 object per page, found %v", len(page.Objects))
+		}
+		pages++
+	}
+	if pages != 3 {
+		t.Errorf("expected 3 pages, found %v", pages)
+	}
+
+	_, err = client.ListObjects("missing")
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "NoSuchBucket" {
+		t.Errorf("err should be a NoSuchBucket error, found : %v", err)
+	}
+}
+
+func TestDeleteObjects(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.pageSize = 1000
+	var keys []string
+	for i := 0; i < 1500; i++ {
+		key := fmt.Sprintf("frames/%04d.png", i)
+		storage.put("bucket", key, key)
+		keys = append(keys, key)
+	}
+	storage.put("bucket", "locked/frame.png", "locked")
+	storage.put("bucket", "scene.blend", "scene")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	deleted, err := client.DeleteObjects("bucket", append(keys, "locked/frame.png"))
+	var deleteErr *DeleteObjectsError
+	if !errors.As(err, &deleteErr) || len(deleteErr.Errors) != 1 || deleteErr.Errors[0].Key != "locked/frame.png" {
+		t.Errorf("err should list the locked key, found : %v", err)
+	}
+	expectedErrorString := "could not delete 1 objects : locked/frame.png (AccessDenied: Access Denied)"
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+	if deleted != 1500 || storage.requestCount("DELETEOBJECTS") != 2 {
+		t.Errorf("expected 1500 objects deleted in 2 requests, found %v in %v", deleted, storage.requestCount("DELETEOBJECTS"))
+	}
+	if _, exists := storage.get("bucket", "scene.blend"); !exists {
+		t.Error("scene.blend should not be deleted")
+	}
+}
+
+func TestDeletePrefixAndBucket(t *testing.T) {
+	storage := newFakeS3(t)
+	for _, key := range []string{"results/0.png", "results/1.png", "results/2.png", "inputs/scene.blend"} {
+		storage.put("bucket", key, key)
+	}
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	deleted, err := client.DeletePrefix("bucket", "results/")
+	if err != nil || deleted != 3 {
+		t.Errorf("expected 3 objects deleted, found %v (%v)", deleted, err)
+	}
+	if _, exists := storage.get("bucket", "inputs/scene.blend"); !exists {
+		t.Error("inputs/scene.blend should not be deleted")
+	}
+
+	err = client.DeleteBucket("bucket")
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "BucketNotEmpty" {
+		t.Errorf("err should be a BucketNotEmpty error, found : %v", err)
+	}
+
+	// Unfinished multipart uploads, such as the ones kept to resume transfers, are aborted as well
+	for _, key := range []string{"dataset-0.tar", "dataset-1.tar", "dataset-2.tar"} {
+		_, err := client.s3.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{Bucket: aws.String("bucket"), Key: aws.String(key)})
+		if err != nil {
+			t.Fatalf("err should be equal to nil: %v", err)
+		}
+	}
+
+	err = client.DeleteBucket("bucket", DeleteBucketOptions{Force: true})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if _, exists := storage.buckets["bucket"]; exists {
+		t.Error("the bucket should be deleted")
+	}
+
+	// Uploads which could not be aborted are reported like objects which could not be deleted
+	storage.put("locked", "inputs/scene.blend", "scene")
+	_, err = client.s3.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{Bucket: aws.String("locked"), Key: aws.String("locked/dataset.tar")})
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+
+	deleted, err = client.EmptyBucket("locked")
+	expectedErrorString := "could not delete 1 objects : locked/dataset.tar (AccessDenied: AccessDenied)"
+	if deleted != 1 || err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v (%v deleted)", err, deleted)
+	}
+}
+
+func TestObjectTags(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("results", "frames/0001.png", "frame")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	tags, err := client.GetObjectTags("results", "frames/0001.png")
+	if err != nil || len(tags) != 0 {
+		t.Errorf("expected no tags, found %v (%v)", tags, err)
+	}
+
+	expectedTags := map[string]string{"task": "6f1d5dbe", "stage": "render"}
+	err = client.PutObjectTags("results", "frames/0001.png", expectedTags)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	tags, err = client.GetObjectTags("results", "frames/0001.png")
+	if err != nil || !reflect.DeepEqual(tags, expectedTags) {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedTags)
+		t.Errorf("found    : %v (%v)", tags, err)
+	}
+
+	// Tags are replaced, not merged
+	err = client.PutObjectTags("results", "frames/0001.png", map[string]string{"stage": "archived"})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if object, _ := storage.get("results", "frames/0001.png"); !reflect.DeepEqual(object.tags, map[string]string{"stage": "archived"}) {
+		t.Errorf("unexpected tags : %v", object.tags)
+	}
+
+	err = client.PutObjectTags("results", "missing", expectedTags)
+	var noSuchKey *smithy.GenericAPIError
+	if !errors.As(err, &noSuchKey) || noSuchKey.Code != "NoSuchKey" || !strings.HasPrefix(err.Error(), "could not put tags on object (results/missing) due to the following error : ") {
+		t.Errorf("unexpected error : %v", err)
+	}
+}
@@ -3,99 +3,80 @@ package qarnot
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/redat00/qarnot-sdk-go/internal/helpers"
 )
 
 type Client struct {
-	httpClient *http.Client
-	url        string
-	apiKey     string
-	version    string
-	s3         *s3.Client
+	httpClient  *http.Client
+	url         string
+	apiKey      string
+	version     string
+	userAgent   string
+	s3          *s3.Client
+	retryPolicy *RetryPolicy
+	limiter     *limiter
 }
 
-// Since the API is not returning consistent errors, we create
-// different struct to accomodate them all. If you want more
-// information about it, you can check the Github issue :
-// https://github.com/redat00/qarnot-sdk-go/issues/7
-
-type firstErrorType struct {
-	Message string `json:"message"`
-}
-
-func (e *firstErrorType) getErrorString() string {
-	return e.Message
-}
-
-type secondErrorType struct {
-	Error firstErrorType `json:"error"`
-}
-
-func (e *secondErrorType) getErrorString() string {
-	return e.Error.Message
-}
-
-type thirdErrorType struct {
-	Errors map[string][]string `json:"errors"`
-}
-
-func (e *thirdErrorType) getErrorString() string {
-	return fmt.Sprintf("%v", e.Errors)
-}
-
-func getErrorStringFromBody(rawMsg []byte) (string, error) {
-	var errorString string
+func (c *Client) sendRequest(ctx context.Context, method string, payload []byte, headers map[string]string, endpoint string, options ...func(*http.Request) error) ([]byte, int, error) {
+	var body []byte
+	var statusCode int
+	var err error
 
-	if strings.Contains(string(rawMsg), `"errors": {`) {
-		var actualError thirdErrorType
-		if err := json.Unmarshal(rawMsg, &actualError); err != nil {
-			return "", helpers.FormatJsonUnmarshalError(err)
+	for attempt := 1; ; attempt++ {
+		var retryAfter time.Duration
+		body, statusCode, retryAfter, err = c.doRequest(ctx, method, payload, headers, endpoint, options...)
+
+		// Errors happening while building the request will not go away by retrying
+		var transportError *TransportError
+		var apiError *APIError
+		var retryableError error
+		if errors.As(err, &transportError) {
+			retryableError = err
+		} else if err != nil && !errors.As(err, &apiError) {
+			return body, statusCode, err
 		}
-		errorString = actualError.getErrorString()
-	} else if strings.Contains(string(rawMsg), `"error":{`) {
-		var actualError secondErrorType
-		if err := json.Unmarshal(rawMsg, &actualError); err != nil {
-			return "", helpers.FormatJsonUnmarshalError(err)
+
+		if attempt >= c.retryPolicy.maxAttempts() || !c.retryPolicy.shouldRetry(method, statusCode, retryableError) {
+			return body, statusCode, err
 		}
-		errorString = actualError.getErrorString()
-	} else {
-		var actualError firstErrorType
-		if err := json.Unmarshal(rawMsg, &actualError); err != nil {
-			return "", helpers.FormatJsonUnmarshalError(err)
+
+		if sleepErr := sleepWithContext(ctx, c.retryPolicy.backoff(attempt, retryAfter)); sleepErr != nil {
+			return body, statusCode, err
 		}
-		errorString = actualError.getErrorString()
 	}
-
-	return errorString, nil
 }
 
-func (c *Client) sendRequest(method string, payload []byte, headers map[string]string, endpoint string, options ...func(*http.Request) error) ([]byte, int, error) {
+// Send a single request to the API, returning the body, the status code and the
+// delay asked by the API through the `Retry-After` header if any
+func (c *Client) doRequest(ctx context.Context, method string, payload []byte, headers map[string]string, endpoint string, options ...func(*http.Request) error) ([]byte, int, time.Duration, error) {
 	// Build the request using url and endpoint
 	var req *http.Request
 	var err error
 	if len(payload) > 0 {
-		req, err = http.NewRequest(method, fmt.Sprintf("%v/%v/%v", c.url, c.version, endpoint), bytes.NewReader(payload))
+		req, err = http.NewRequestWithContext(ctx, method, fmt.Sprintf("%v/%v/%v", c.url, c.version, endpoint), bytes.NewReader(payload))
 	} else {
-		req, err = http.NewRequest(method, fmt.Sprintf("%v/%v/%v", c.url, c.version, endpoint), nil)
+		req, err = http.NewRequestWithContext(ctx, method, fmt.Sprintf("%v/%v/%v", c.url, c.version, endpoint), nil)
 	}
 	if err != nil {
-		return []byte{}, 0, fmt.Errorf("could not create request due to the following error: %v", err)
+		return []byte{}, 0, 0, fmt.Errorf("could not create request due to the following error: %w", err)
 	}
 
 	// Add required headers to request
 	req.Header.Add("Authorization", c.apiKey)
 	req.Header.Add("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
 	// Add more headers
 	for k, v := range headers {
@@ -106,40 +87,42 @@ func (c *Client) sendRequest(method string, payload []byte, headers map[string]s
 	for _, option := range options {
 		err = option(req)
 		if err != nil {
-			return []byte{}, 0, fmt.Errorf("could not apply option during request creation: %v", err)
+			return []byte{}, 0, 0, fmt.Errorf("could not apply option during request creation: %w", err)
 		}
 	}
 
+	// Wait for the client side limits to let the request go
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return []byte{}, 0, 0, &TransportError{Method: method, URL: req.URL.String(), Err: err}
+	}
+	defer release()
+
 	// Launch the request using the HTTP client
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		panic(fmt.Errorf("an error happened during the execution of the request: %v", err))
+		return []byte{}, 0, 0, &TransportError{Method: method, URL: req.URL.String(), Err: err}
 	}
 	defer resp.Body.Close()
 
 	// Read the content of the body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		panic(err)
+		return []byte{}, resp.StatusCode, 0, &TransportError{Method: method, URL: req.URL.String(), Err: err}
 	}
 
 	// Check that the request did not fail
 	if resp.StatusCode >= 400 {
-		var rawMsg json.RawMessage
-		if err := json.Unmarshal([]byte(body), &rawMsg); err != nil {
-			return []byte{}, 0, helpers.FormatJsonUnmarshalError(err)
-		}
-
-		errorString, err := getErrorStringFromBody(rawMsg)
-		if err != nil {
-			panic(err)
+		apiError := newAPIError(resp.StatusCode, body)
+		if apiError.RequestID == "" {
+			apiError.RequestID = resp.Header.Get("X-Request-Id")
 		}
 
-		return []byte{}, resp.StatusCode, fmt.Errorf("[HTTP %v] %v", resp.StatusCode, errorString)
+		return []byte{}, resp.StatusCode, parseRetryAfter(resp.Header), apiError
 	}
 
 	// Return the response
-	return body, resp.StatusCode, nil
+	return body, resp.StatusCode, 0, nil
 }
 
 type QarnotConfig struct {
@@ -148,39 +131,85 @@ type QarnotConfig struct {
 	Email      string
 	Version    string
 	StorageUrl string
+	// Optional, when nil failed requests to the API are not retried, while the S3 client keeps
+	// the default retryer of the AWS SDK (or the one of the configuration given using `WithAWSConfig`)
+	RetryPolicy *RetryPolicy
+	// Optional, requests are not throttled when nil
+	Limits *Limits
 }
 
-func NewClient(qarnotConfig *QarnotConfig) (*Client, error) {
+// Create a new client from the given configuration
+// Options can be given to customize the HTTP and the S3 clients, see `Option`
+func NewClient(qarnotConfig *QarnotConfig, options ...Option) (*Client, error) {
+	return NewClientWithContext(context.Background(), qarnotConfig, options...)
+}
+
+// Same as `NewClient`, but the given context is used while loading the S3 configuration
+func NewClientWithContext(ctx context.Context, qarnotConfig *QarnotConfig, options ...Option) (*Client, error) {
+	opts := newClientOptions(options...)
+
 	// Create an HTTP client
-	httpClient := &http.Client{
-		Timeout:   15 * time.Second,
-		Transport: http.DefaultTransport,
-	}
+	httpClient := opts.buildHTTPClient()
+
+	// Create an AWS Config, unless one was given
+	var awsConfig aws.Config
+	if opts.awsConfig != nil {
+		awsConfig = opts.awsConfig.Copy()
+		if awsConfig.Credentials == nil {
+			awsConfig.Credentials = credentials.NewStaticCredentialsProvider(qarnotConfig.Email, qarnotConfig.ApiKey, "")
+		}
+		if awsConfig.Region == "" {
+			awsConfig.Region = "default"
+		}
+		if qarnotConfig.RetryPolicy != nil {
+			awsConfig.Retryer = qarnotConfig.RetryPolicy.s3Retryer
+		}
+	} else {
+		awsOptions := []func(*config.LoadOptions) error{
+			config.WithRegion("default"),
+			config.WithCredentialsProvider(
+				credentials.NewStaticCredentialsProvider(qarnotConfig.Email, qarnotConfig.ApiKey, ""),
+			),
+		}
+		if qarnotConfig.RetryPolicy != nil {
+			awsOptions = append(awsOptions, config.WithRetryer(qarnotConfig.RetryPolicy.s3Retryer))
+		}
 
-	// Create an AWS Config
-	awsConfig, err := config.LoadDefaultConfig(
-		context.TODO(),
-		config.WithRegion("default"),
-		config.WithCredentialsProvider(
-			credentials.NewStaticCredentialsProvider(qarnotConfig.Email, qarnotConfig.ApiKey, ""),
-		),
-	)
-	if err != nil {
-		return &Client{}, fmt.Errorf("could not create S3 configuration: %v", err)
+		var err error
+		awsConfig, err = config.LoadDefaultConfig(ctx, awsOptions...)
+		if err != nil {
+			return &Client{}, fmt.Errorf("could not create S3 configuration: %w", err)
+		}
 	}
 
 	// Create an S3 Client
-	s3Client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(qarnotConfig.StorageUrl)
-	})
+	limiter := newLimiter(qarnotConfig.Limits)
+	s3Options := []func(*s3.Options){
+		func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(qarnotConfig.StorageUrl)
+			if opts.transport != http.DefaultTransport {
+				o.HTTPClient = &http.Client{Transport: opts.transport}
+			}
+			if opts.userAgent != "" {
+				o.APIOptions = append(o.APIOptions, awsmiddleware.AddUserAgentKey(opts.userAgent))
+			}
+			if limiter != nil {
+				o.APIOptions = append(o.APIOptions, limiter.s3Middleware)
+			}
+		},
+	}
+	s3Client := s3.NewFromConfig(awsConfig, append(s3Options, opts.s3Options...)...)
 
 	// Create the actual API client
 	client := Client{
-		httpClient: httpClient,
-		url:        qarnotConfig.ApiUrl,
-		apiKey:     qarnotConfig.ApiKey,
-		version:    qarnotConfig.Version,
-		s3:         s3Client,
+		httpClient:  httpClient,
+		url:         qarnotConfig.ApiUrl,
+		apiKey:      qarnotConfig.ApiKey,
+		version:     qarnotConfig.Version,
+		userAgent:   opts.userAgent,
+		s3:          s3Client,
+		retryPolicy: qarnotConfig.RetryPolicy,
+		limiter:     limiter,
 	}
 
 	// Return the client
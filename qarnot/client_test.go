@@ -0,0 +1,191 @@
+package qarnot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSendRequestWithCanceledContext(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprint(w, "{\"storage\": \"https://storage.qarnot.com\"}")
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = client.GetSettingsWithContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err should wrap context.DeadlineExceeded, found : %v", err)
+	}
+
+	var transportError *TransportError
+	if !errors.As(err, &transportError) {
+		t.Errorf("err should be a *TransportError, found : %T", err)
+	}
+}
+
+func TestSendRequestTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	_, err = client.GetSettings()
+	var transportError *TransportError
+	if !errors.As(err, &transportError) {
+		t.Fatalf("err should be a *TransportError, found : %v", err)
+	}
+
+	if transportError.Method != "GET" {
+		t.Errorf("expected : GET")
+		t.Errorf("found    : %v", transportError.Method)
+	}
+}
+
+func TestSendRequestAPIError(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/tasks/notfound" {
+				w.WriteHeader(404)
+				fmt.Fprint(w, "{\"message\": \"No such task: notfound\"}")
+			} else {
+				w.WriteHeader(502)
+				fmt.Fprint(w, "<html>Bad Gateway</html>")
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	_, err = client.GetTaskInfo("notfound")
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("err should be an *APIError, found : %v", err)
+	}
+
+	if apiError.StatusCode != 404 || apiError.Message != "No such task: notfound" {
+		t.Error("different values.")
+		t.Errorf("expected : 404 No such task: notfound")
+		t.Errorf("found    : %v %v", apiError.StatusCode, apiError.Message)
+	}
+
+	_, err = client.GetSettings()
+	if !errors.As(err, &apiError) {
+		t.Fatalf("err should be an *APIError, found : %v", err)
+	}
+
+	expectedErrorString := "[HTTP 502] <html>Bad Gateway</html>"
+	if apiError.Error() != expectedErrorString {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", apiError.Error())
+	}
+}
+
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/settings" {
+				fmt.Fprint(w, "{\"storage\": \""+r.Header.Get("User-Agent")+"\"}")
+			} else {
+				time.Sleep(200 * time.Millisecond)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	transport := recordingTransport{}
+	client, err := NewClient(
+		&qarnotConfig,
+		WithTransport(&transport),
+		WithTimeout(50*time.Millisecond),
+		WithUserAgent("my-app/1.0"),
+	)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	settings, err := client.GetSettings()
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := Settings{Storage: "my-app/1.0"}
+	if settings != expectedData {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedData)
+		t.Errorf("found    : %v", settings)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Errorf("the request should have gone through the given transport")
+	}
+
+	_, err = client.GetVersions()
+	var transportError *TransportError
+	if !errors.As(err, &transportError) {
+		t.Errorf("the request should have timed out, found : %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package qarnot
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+const (
+	DefaultApiUrl     = "https://api.qarnot.com"
+	DefaultVersion    = "v1"
+	DefaultStorageUrl = "https://storage.qarnot.com"
+)
+
+// Environment variables read by `LoadConfig`
+const (
+	EnvClientToken  = "QARNOT_CLIENT_TOKEN"
+	EnvClusterUrl   = "QARNOT_CLUSTER_URL"
+	EnvStorageUrl   = "QARNOT_STORAGE_URL"
+	EnvAccountEmail = "QARNOT_ACCOUNT_EMAIL"
+	EnvApiVersion   = "QARNOT_API_VERSION"
+	EnvConfigFile   = "QARNOT_CONFIG_FILE"
+	EnvProfile      = "QARNOT_PROFILE"
+)
+
+// Name of the configuration file looked up in the working directory when `QARNOT_CONFIG_FILE` is not set
+const DefaultConfigFile = "qarnot.conf"
+
+// Will load the configuration from the environment and from the configuration file
+//
+// Values are resolved in the following order :
+//   - environment variables (`QARNOT_CLIENT_TOKEN`, `QARNOT_CLUSTER_URL`, `QARNOT_STORAGE_URL`,
+//     `QARNOT_ACCOUNT_EMAIL` and `QARNOT_API_VERSION`)
+//   - the configuration file pointed by `QARNOT_CONFIG_FILE`, or `qarnot.conf` in the working
+//     directory, using the profile named by `QARNOT_PROFILE` if any (see `LoadConfigFile`)
+//   - the defaults for `ApiUrl` and `Version`
+//
+// When it is still missing, `StorageUrl` is then retrieved from the API using `GetSettings`, and an
+// error is returned if the API can't be reached. `Email` is never retrieved from the API, and is
+// left empty when not set
+func LoadConfig() (*QarnotConfig, error) {
+	return LoadConfigWithContext(context.Background())
+}
+
+// Same as `LoadConfig`, but accepting a context to control cancellation and deadlines
+func LoadConfigWithContext(ctx context.Context) (*QarnotConfig, error) {
+	qarnotConfig := QarnotConfig{}
+
+	// Read the configuration file, which is only required when explicitly given or when a profile is selected
+	path, explicit := os.LookupEnv(EnvConfigFile)
+	if !explicit {
+		path = DefaultConfigFile
+	}
+	profile := os.Getenv(EnvProfile)
+	fileConfig, err := LoadConfigFile(path, profile)
+	if err == nil {
+		qarnotConfig = *fileConfig
+	} else if explicit || profile != "" || !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// Environment variables have the priority over the file
+	for env, field := range map[string]*string{
+		EnvClientToken:  &qarnotConfig.ApiKey,
+		EnvClusterUrl:   &qarnotConfig.ApiUrl,
+		EnvStorageUrl:   &qarnotConfig.StorageUrl,
+		EnvAccountEmail: &qarnotConfig.Email,
+		EnvApiVersion:   &qarnotConfig.Version,
+	} {
+		if value := os.Getenv(env); value != "" {
+			*field = value
+		}
+	}
+
+	if qarnotConfig.ApiKey == "" {
+		return nil, fmt.Errorf("could not load configuration : no token found in %v nor in the configuration file", EnvClientToken)
+	}
+	if qarnotConfig.ApiUrl == "" {
+		qarnotConfig.ApiUrl = DefaultApiUrl
+	}
+	if qarnotConfig.Version == "" {
+		qarnotConfig.Version = DefaultVersion
+	}
+
+	// Ask the API for the storage URL when it is missing
+	if qarnotConfig.StorageUrl == "" {
+		client, err := NewClientWithContext(ctx, &qarnotConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		settings, err := client.GetSettingsWithContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not load configuration due to the following error : %w", err)
+		}
+		qarnotConfig.StorageUrl = settings.Storage
+	}
+	if qarnotConfig.StorageUrl == "" {
+		qarnotConfig.StorageUrl = DefaultStorageUrl
+	}
+
+	return &qarnotConfig, nil
+}
+
+// Will load the configuration from an INI file compatible with the `qarnot.conf` of the Python SDK
+//
+//	[cluster]
+//	url=https://api.qarnot.com
+//	version=v1
+//
+//	[client]
+//	token=MY_SUPER_TOKEN
+//	email=test@example.org
+//
+//	[storage]
+//	url=https://storage.qarnot.com
+//
+// Named profiles live in the same file, with their sections prefixed by the name of the
+// profile (`[staging.cluster]`, `[staging.client]` and `[staging.storage]`). When the
+// profile is not empty, its values are used over the ones of the unprefixed sections
+//
+// Missing values are left empty, no default is applied
+func LoadConfigFile(path string, profile string) (*QarnotConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open configuration file due to the following error : %w", err)
+	}
+	defer file.Close()
+
+	sections, err := parseIni(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse configuration file (%v) due to the following error : %w", path, err)
+	}
+
+	prefixes := []string{""}
+	if profile != "" {
+		found := false
+		for name := range sections {
+			if strings.HasPrefix(name, profile+".") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("could not find profile (%v) in configuration file (%v)", profile, path)
+		}
+		prefixes = append(prefixes, profile+".")
+	}
+
+	qarnotConfig := QarnotConfig{}
+	for _, prefix := range prefixes {
+		for key, field := range map[string]*string{
+			"cluster.url":     &qarnotConfig.ApiUrl,
+			"cluster.version": &qarnotConfig.Version,
+			"client.token":    &qarnotConfig.ApiKey,
+			"client.email":    &qarnotConfig.Email,
+			"storage.url":     &qarnotConfig.StorageUrl,
+		} {
+			section, name, _ := strings.Cut(key, ".")
+			if value := sections[prefix+section][name]; value != "" {
+				*field = value
+			}
+		}
+	}
+
+	return &qarnotConfig, nil
+}
+
+// Parse a minimal INI file : sections, `key=value` or `key: value` pairs, and comments starting with `#` or `;`
+func parseIni(file *os.File) (map[string]map[string]string, error) {
+	sections := map[string]map[string]string{}
+	current := ""
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := sections[current]; !ok {
+				sections[current] = map[string]string{}
+			}
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if colonKey, colonValue, colonFound := strings.Cut(line, ":"); colonFound && (!found || len(colonKey) < len(key)) {
+			key, value, found = colonKey, colonValue, true
+		}
+		if !found || current == "" {
+			return nil, fmt.Errorf("invalid line %v : %v", lineNumber, line)
+		}
+
+		sections[current][strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+
+	return sections, scanner.Err()
+}
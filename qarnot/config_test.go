@@ -0,0 +1,146 @@
+package qarnot
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfigFile = `
+# Default profile
+[cluster]
+url=https://api.qarnot.com
+
+[client]
+token=MY_SUPER_TOKEN
+
+[storage]
+url: https://storage.qarnot.com
+
+; Staging profile
+[staging.cluster]
+url=https://api.staging.qarnot.com
+version=v2
+
+[staging.client]
+token=MY_STAGING_TOKEN
+email=staging@example.org
+`
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qarnot.conf")
+	if err := os.WriteFile(path, []byte(testConfigFile), 0600); err != nil {
+		t.Fatalf("could not write configuration file: %v", err)
+	}
+
+	qarnotConfig, err := LoadConfigFile(path, "")
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := QarnotConfig{
+		ApiUrl:     "https://api.qarnot.com",
+		ApiKey:     "MY_SUPER_TOKEN",
+		StorageUrl: "https://storage.qarnot.com",
+	}
+	if *qarnotConfig != expectedData {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", *qarnotConfig)
+	}
+
+	qarnotConfig, err = LoadConfigFile(path, "staging")
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData = QarnotConfig{
+		ApiUrl:     "https://api.staging.qarnot.com",
+		ApiKey:     "MY_STAGING_TOKEN",
+		Email:      "staging@example.org",
+		Version:    "v2",
+		StorageUrl: "https://storage.qarnot.com",
+	}
+	if *qarnotConfig != expectedData {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", *qarnotConfig)
+	}
+
+	_, err = LoadConfigFile(path, "unknown")
+	if err == nil {
+		t.Error("err should not be equal to nil for an unknown profile")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "xxx" {
+				w.WriteHeader(401)
+				fmt.Fprint(w, "{\"message\": \"Invalid token\"}")
+			} else if r.URL.Path == "/v1/settings" {
+				fmt.Fprint(w, "{\"storage\": \"https://storage.example.org\"}")
+			} else {
+				w.WriteHeader(503)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	t.Setenv(EnvConfigFile, filepath.Join(t.TempDir(), "missing.conf"))
+	if _, err := LoadConfig(); err == nil {
+		t.Error("err should not be equal to nil when the given configuration file is missing")
+	}
+
+	// A profile can't be selected without a configuration file
+	os.Unsetenv(EnvConfigFile)
+	t.Setenv(EnvProfile, "staging")
+	if _, err := LoadConfig(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("err should be a missing file error, found : %v", err)
+	}
+	os.Unsetenv(EnvProfile)
+
+	emptyPath := filepath.Join(t.TempDir(), "qarnot.conf")
+	if err := os.WriteFile(emptyPath, []byte{}, 0600); err != nil {
+		t.Fatalf("could not write configuration file: %v", err)
+	}
+	t.Setenv(EnvConfigFile, emptyPath)
+	t.Setenv(EnvClientToken, "xxx")
+	t.Setenv(EnvClusterUrl, srv.URL)
+
+	qarnotConfig, err := LoadConfig()
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Version:    "v1",
+		StorageUrl: "https://storage.example.org",
+	}
+	if *qarnotConfig != expectedData {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", *qarnotConfig)
+	}
+
+	// Failing to retrieve the storage URL is reported instead of falling back to the default one
+	t.Setenv(EnvClientToken, "invalid")
+	_, err = LoadConfig()
+	if !IsUnauthorized(err) {
+		t.Errorf("err should be an unauthorized error, found : %v", err)
+	}
+
+	t.Setenv(EnvClientToken, "xxx")
+	t.Setenv(EnvClusterUrl, srv.URL+"/unavailable")
+	if _, err = LoadConfig(); err == nil {
+		t.Error("err should not be equal to nil when the API is unavailable")
+	}
+}
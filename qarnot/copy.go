@@ -0,0 +1,228 @@
+package qarnot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// Objects larger than this cannot be copied in a single request, and are copied in multiple parts
+	MultipartCopyThreshold int64 = 5 * 1024 * 1024 * 1024
+	// Size of the parts of a multipart copy, made larger for objects which would need more than `MaxUploadParts` parts
+	MultipartCopyPartSize int64 = 512 * 1024 * 1024
+)
+
+// Build the copy source of an object, escaping its key but keeping its slashes
+func copySource(bucketName string, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return url.PathEscape(bucketName) + "/" + strings.Join(segments, "/")
+}
+
+// Copy an object, from a bucket to another one or within the same bucket
+// The copy is done by the storage, keeping the content type and the metadata of the object
+// Objects larger than 5GB are copied in multiple parts, in parallel
+func (c *Client) CopyObject(sourceBucket string, sourceKey string, destinationBucket string, destinationKey string) error {
+	return c.CopyObjectWithContext(context.Background(), sourceBucket, sourceKey, destinationBucket, destinationKey)
+}
+
+// Same as `CopyObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) CopyObjectWithContext(ctx context.Context, sourceBucket string, sourceKey string, destinationBucket string, destinationKey string) error {
+	err := c.copyObject(ctx, sourceBucket, sourceKey, destinationBucket, destinationKey, MultipartCopyThreshold, MultipartCopyPartSize)
+	if err != nil {
+		return fmt.Errorf("could not copy object (%v/%v) due to the following error : %w", sourceBucket, sourceKey, err)
+	}
+
+	return nil
+}
+
+// Copy an object, in parts of at least `partSize` when it is larger than `threshold`
+func (c *Client) copyObject(ctx context.Context, sourceBucket string, sourceKey string, destinationBucket string, destinationKey string, threshold int64, partSize int64) error {
+	head, err := c.s3.HeadObject(
+		ctx,
+		&s3.HeadObjectInput{
+			Bucket: &sourceBucket,
+			Key:    &sourceKey,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	source := copySource(sourceBucket, sourceKey)
+	size := aws.ToInt64(head.ContentLength)
+	if size <= threshold {
+		_, err := c.s3.CopyObject(
+			ctx,
+			&s3.CopyObjectInput{
+				Bucket:            &destinationBucket,
+				Key:               &destinationKey,
+				CopySource:        &source,
+				CopySourceIfMatch: head.ETag,
+				MetadataDirective: types.MetadataDirectiveCopy,
+			},
+		)
+		return err
+	}
+
+	// Multipart uploads do not copy anything from the source, so the metadata has to be given again
+	upload, err := c.s3.CreateMultipartUpload(
+		ctx,
+		&s3.CreateMultipartUploadInput{
+			Bucket:             &destinationBucket,
+			Key:                &destinationKey,
+			ContentType:        head.ContentType,
+			CacheControl:       head.CacheControl,
+			ContentDisposition: head.ContentDisposition,
+			ContentEncoding:    head.ContentEncoding,
+			ContentLanguage:    head.ContentLanguage,
+			Metadata:           head.Metadata,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	partSize = multipartPartSize(size, partSize, MaxUploadParts)
+	partsCount := (size + partSize - 1) / partSize
+	parts := make([]types.CompletedPart, partsCount)
+	numbers := make([]int32, partsCount)
+	for i := range numbers {
+		numbers[i] = int32(i + 1)
+	}
+
+	var mu sync.Mutex
+	err = forEachParallel(ctx, DefaultTransferConcurrency, numbers, func(ctx context.Context, number int32) error {
+		start := int64(number-1) * partSize
+		end := min(start+partSize, size) - 1
+
+		output, err := c.s3.UploadPartCopy(
+			ctx,
+			&s3.UploadPartCopyInput{
+				Bucket:            &destinationBucket,
+				Key:               &destinationKey,
+				UploadId:          upload.UploadId,
+				PartNumber:        aws.Int32(number),
+				CopySource:        &source,
+				CopySourceIfMatch: head.ETag,
+				CopySourceRange:   aws.String(fmt.Sprintf("bytes=%v-%v", start, end)),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("part %v : %w", number, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		parts[number-1] = types.CompletedPart{PartNumber: aws.Int32(number), ETag: output.CopyPartResult.ETag}
+		return nil
+	})
+	if err == nil {
+		_, err = c.s3.CompleteMultipartUpload(
+			ctx,
+			&s3.CompleteMultipartUploadInput{
+				Bucket:          &destinationBucket,
+				Key:             &destinationKey,
+				UploadId:        upload.UploadId,
+				MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+			},
+		)
+	}
+	if err != nil {
+		c.s3.AbortMultipartUpload(
+			context.Background(),
+			&s3.AbortMultipartUploadInput{
+				Bucket:   &destinationBucket,
+				Key:      &destinationKey,
+				UploadId: upload.UploadId,
+			},
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Move an object, by copying it using `CopyObject` and then deleting the source
+// The source is only deleted once copied
+func (c *Client) MoveObject(sourceBucket string, sourceKey string, destinationBucket string, destinationKey string) error {
+	return c.MoveObjectWithContext(context.Background(), sourceBucket, sourceKey, destinationBucket, destinationKey)
+}
+
+// Same as `MoveObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) MoveObjectWithContext(ctx context.Context, sourceBucket string, sourceKey string, destinationBucket string, destinationKey string) error {
+	// The object would be deleted once copied onto itself
+	if sourceBucket == destinationBucket && sourceKey == destinationKey {
+		return fmt.Errorf("could not move object (%v/%v) : the source and the destination are the same", sourceBucket, sourceKey)
+	}
+
+	err := c.copyObject(ctx, sourceBucket, sourceKey, destinationBucket, destinationKey, MultipartCopyThreshold, MultipartCopyPartSize)
+	if err != nil {
+		return fmt.Errorf("could not move object (%v/%v) due to the following error : %w", sourceBucket, sourceKey, err)
+	}
+
+	_, err = c.s3.DeleteObject(
+		ctx,
+		&s3.DeleteObjectInput{
+			Bucket: &sourceBucket,
+			Key:    &sourceKey,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not move object (%v/%v) due to the following error : %w", sourceBucket, sourceKey, err)
+	}
+
+	return nil
+}
+
+// Copy every object whose key starts with `sourcePrefix`, replacing the prefix by `destinationPrefix`
+// Objects are copied in parallel using `CopyObject`, stopping at the first error
+// Returns the number of copied objects
+func (c *Client) CopyPrefix(sourceBucket string, sourcePrefix string, destinationBucket string, destinationPrefix string) (int, error) {
+	return c.CopyPrefixWithContext(context.Background(), sourceBucket, sourcePrefix, destinationBucket, destinationPrefix)
+}
+
+// Same as `CopyPrefix`, but accepting a context to control cancellation and deadlines
+func (c *Client) CopyPrefixWithContext(ctx context.Context, sourceBucket string, sourcePrefix string, destinationBucket string, destinationPrefix string) (int, error) {
+	if sourceBucket == destinationBucket && strings.HasPrefix(destinationPrefix, sourcePrefix) {
+		return 0, fmt.Errorf("could not copy prefix : %v is within %v, copied objects would be copied again", destinationPrefix, sourcePrefix)
+	}
+
+	copied := 0
+
+	pager := c.PaginateObjects(sourceBucket, ListObjectsOptions{Prefix: sourcePrefix})
+	for pager.HasMorePages() {
+		page, err := pager.NextPageWithContext(ctx)
+		if err != nil {
+			return copied, fmt.Errorf("could not copy prefix due to the following error : %w", err)
+		}
+
+		var mu sync.Mutex
+		err = forEachParallel(ctx, 2*DefaultTransferConcurrency, page.Objects, func(ctx context.Context, object BucketObject) error {
+			destinationKey := destinationPrefix + strings.TrimPrefix(object.Name, sourcePrefix)
+			err := c.copyObject(ctx, sourceBucket, object.Name, destinationBucket, destinationKey, MultipartCopyThreshold, MultipartCopyPartSize)
+			if err != nil {
+				return fmt.Errorf("could not copy object (%v/%v) due to the following error : %w", sourceBucket, object.Name, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			copied++
+			return nil
+		})
+		if err != nil {
+			return copied, fmt.Errorf("could not copy prefix due to the following error : %w", err)
+		}
+	}
+
+	return copied, nil
+}
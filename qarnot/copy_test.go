@@ -0,0 +1,119 @@
+package qarnot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestCopyObject(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("results", "placeholder", "")
+	storage.put("datasets", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	err := client.PutObject("results", "frames/0001.png", strings.NewReader("0123456789"), PutOptions{Metadata: map[string]string{"frame": "1"}})
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+
+	err = client.CopyObject("results", "frames/0001.png", "datasets", "stage-2/0001.png")
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	object, _ := storage.get("datasets", "stage-2/0001.png")
+	if string(object.data) != "0123456789" || object.contentType != "image/png" || object.metadata["frame"] != "1" {
+		t.Errorf("unexpected object : %+v", object)
+	}
+
+	// Large objects are copied in multiple parts, keeping their metadata
+	err = client.copyObject(context.Background(), "results", "frames/0001.png", "datasets", "stage-2/multipart.png", 5, 4)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	object, _ = storage.get("datasets", "stage-2/multipart.png")
+	if string(object.data) != "0123456789" || object.contentType != "image/png" || object.metadata["frame"] != "1" || !strings.HasSuffix(object.etag, `-3"`) {
+		t.Errorf("unexpected object : %+v", object)
+	}
+
+	err = client.CopyObject("results", "missing", "datasets", "missing")
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || !strings.HasPrefix(err.Error(), "could not copy object (results/missing) due to the following error : ") {
+		t.Errorf("unexpected error : %v", err)
+	}
+}
+
+func TestMultipartCopyPartSize(t *testing.T) {
+	const mib = 1024 * 1024
+
+	if partSize := multipartPartSize(10*1024*mib, MultipartCopyPartSize, MaxUploadParts); partSize != MultipartCopyPartSize {
+		t.Errorf("expected : %v", MultipartCopyPartSize)
+		t.Errorf("found    : %v", partSize)
+	}
+
+	// The largest object allowed by S3 (5TiB) needs larger parts to fit in 10000 parts
+	size := int64(5 * 1024 * 1024 * mib)
+	partSize := multipartPartSize(size, MultipartCopyPartSize, MaxUploadParts)
+	if partSize != 525*mib || (size+partSize-1)/partSize > MaxUploadParts {
+		t.Errorf("expected : %v", 525*mib)
+		t.Errorf("found    : %v", partSize)
+	}
+}
+
+func TestMoveObject(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "tmp/output.csv", "output")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	err := client.MoveObject("bucket", "tmp/output.csv", "bucket", "final/output.csv")
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if _, exists := storage.get("bucket", "tmp/output.csv"); exists {
+		t.Error("the source should be deleted")
+	}
+	if object, _ := storage.get("bucket", "final/output.csv"); string(object.data) != "output" {
+		t.Errorf("unexpected object : %+v", object)
+	}
+
+	err = client.MoveObject("bucket", "final/output.csv", "bucket", "final/output.csv")
+	expectedErrorString := "could not move object (bucket/final/output.csv) : the source and the destination are the same"
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+	if _, exists := storage.get("bucket", "final/output.csv"); !exists {
+		t.Error("the object should not be deleted")
+	}
+}
+
+func TestCopyPrefix(t *testing.T) {
+	storage := newFakeS3(t)
+	for _, key := range []string{"task-1/0.png", "task-1/1.png", "task-1/logs/out.log", "task-2/0.png"} {
+		storage.put("results", key, key)
+	}
+	storage.put("datasets", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	copied, err := client.CopyPrefix("results", "task-1/", "datasets", "stage-2/")
+	if err != nil || copied != 3 {
+		t.Errorf("expected 3 objects copied, found %v (%v)", copied, err)
+	}
+	for _, key := range []string{"stage-2/0.png", "stage-2/1.png", "stage-2/logs/out.log"} {
+		if _, exists := storage.get("datasets", key); !exists {
+			t.Errorf("%v should be copied", key)
+		}
+	}
+
+	_, err = client.CopyPrefix("results", "task-1/", "results", "task-1/backup/")
+	expectedErrorString := "could not copy prefix : task-1/backup/ is within task-1/, copied objects would be copied again"
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package qarnot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error returned when a request could not reach the API, or when its response
+// could not be read. The underlying error (usually coming from the `net` package)
+// is available through `errors.As` / `errors.Is`
+type TransportError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("an error happened during the execution of the request (%v %v): %v", e.Method, e.URL, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Error returned when the API answered with an HTTP status code of 400 or above
+//
+// `FieldErrors` is only filled for validation errors, and maps each invalid field
+// of the payload to the list of problems found on it
+type APIError struct {
+	StatusCode  int
+	Message     string
+	FieldErrors map[string][]string
+	RequestID   string
+	Body        []byte
+}
+
+func (e *APIError) Error() string {
+	if len(e.FieldErrors) > 0 {
+		return fmt.Sprintf("[HTTP %v] %v", e.StatusCode, e.FieldErrors)
+	}
+	return fmt.Sprintf("[HTTP %v] %v", e.StatusCode, e.Message)
+}
+
+// Since the API is not returning consistent errors, the body is decoded in a
+// struct holding the fields of every known format. If you want more information
+// about it, you can check the Github issue :
+// https://github.com/redat00/qarnot-sdk-go/issues/7
+//
+// - {"message": "..."}
+// - {"error": {"message": "...", "code": 401}, "data": {}}
+// - {"errors": {"field": ["..."]}, "title": "...", "extensions": {"traceId": "..."}}
+type errorBody struct {
+	Message    string          `json:"message"`
+	Error      json.RawMessage `json:"error"`
+	Errors     json.RawMessage `json:"errors"`
+	Title      string          `json:"title"`
+	TraceId    string          `json:"traceId"`
+	Extensions struct {
+		TraceId string `json:"traceId"`
+	} `json:"extensions"`
+}
+
+type nestedErrorBody struct {
+	Message string `json:"message"`
+}
+
+// Build an `APIError` from the status code and the body of a failed response
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiError := APIError{StatusCode: statusCode, Body: body}
+
+	var decoded errorBody
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		// The body is not always something we know how to decode (a proxy error page for
+		// example), in which case we keep the raw body as the message
+		apiError.Message = strings.TrimSpace(string(body))
+	} else {
+		apiError.Message = decoded.Message
+
+		var nested nestedErrorBody
+		if len(decoded.Error) > 0 && json.Unmarshal(decoded.Error, &nested) == nil && nested.Message != "" {
+			apiError.Message = nested.Message
+		}
+
+		var fieldErrors map[string][]string
+		if len(decoded.Errors) > 0 && json.Unmarshal(decoded.Errors, &fieldErrors) == nil && len(fieldErrors) > 0 {
+			apiError.FieldErrors = fieldErrors
+			if apiError.Message == "" {
+				apiError.Message = decoded.Title
+			}
+		}
+
+		apiError.RequestID = decoded.Extensions.TraceId
+		if apiError.RequestID == "" {
+			apiError.RequestID = decoded.TraceId
+		}
+	}
+
+	if apiError.Message == "" {
+		apiError.Message = http.StatusText(statusCode)
+	}
+
+	return &apiError
+}
+
+func hasStatusCode(err error, statusCodes ...int) bool {
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		return false
+	}
+	for _, statusCode := range statusCodes {
+		if apiError.StatusCode == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Will return true if the error was caused by a missing resource (HTTP 404)
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// Will return true if the error was caused by a missing or invalid token (HTTP 401)
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// Will return true if the error was caused by a conflict with the current state of a resource (HTTP 409)
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// Will return true if the error was caused by a validation failure of the payload (HTTP 400 with field errors)
+func IsValidationError(err error) bool {
+	var apiError *APIError
+	return errors.As(err, &apiError) && apiError.StatusCode == http.StatusBadRequest && len(apiError.FieldErrors) > 0
+}
@@ -0,0 +1,85 @@
+package qarnot
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	validationBody := `{
+		"errors": {
+		  "Name": [
+			"The Name field is required."
+		  ]
+		},
+		"type": "https://tools.ietf.org/html/rfc7231#section-6.5.1",
+		"title": "One or more validation errors occurred.",
+		"status": 400,
+		"extensions": {
+		  "traceId": "00-a262138981ea711ace26bbc03d99fd02-425a067b647884a7-01"
+		}
+	  }`
+
+	tests := []struct {
+		statusCode int
+		body       string
+		expected   APIError
+	}{
+		{
+			statusCode: 404,
+			body:       `{"message": "No such task: test"}`,
+			expected:   APIError{StatusCode: 404, Message: "No such task: test"},
+		},
+		{
+			statusCode: 401,
+			body:       `{"error":{"message":"Bad authentication token","code":401},"data":{}}`,
+			expected:   APIError{StatusCode: 401, Message: "Bad authentication token"},
+		},
+		{
+			statusCode: 400,
+			body:       validationBody,
+			expected: APIError{
+				StatusCode:  400,
+				Message:     "One or more validation errors occurred.",
+				FieldErrors: map[string][]string{"Name": {"The Name field is required."}},
+				RequestID:   "00-a262138981ea711ace26bbc03d99fd02-425a067b647884a7-01",
+			},
+		},
+		{
+			statusCode: 503,
+			body:       "",
+			expected:   APIError{StatusCode: 503, Message: "Service Unavailable"},
+		},
+	}
+
+	for _, test := range tests {
+		apiError := newAPIError(test.statusCode, []byte(test.body))
+		test.expected.Body = []byte(test.body)
+
+		if !reflect.DeepEqual(*apiError, test.expected) {
+			t.Error("different values.")
+			t.Errorf("expected : %+v", test.expected)
+			t.Errorf("found    : %+v", *apiError)
+		}
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	notFound := fmt.Errorf("could not get task info due to the following error : %w", &APIError{StatusCode: 404})
+	forbidden := &APIError{StatusCode: 403, Message: "Maximum number of running tasks reached"}
+	validation := &APIError{StatusCode: 400, FieldErrors: map[string][]string{"Name": {"required"}}}
+
+	if !IsNotFound(notFound) || IsNotFound(forbidden) {
+		t.Error("IsNotFound should only match HTTP 404 errors")
+	}
+	if !IsValidationError(validation) || IsValidationError(notFound) {
+		t.Error("IsValidationError should only match HTTP 400 errors with field errors")
+	}
+	if !IsUnauthorized(&APIError{StatusCode: 401}) || !IsConflict(&APIError{StatusCode: 409}) {
+		t.Error("IsUnauthorized and IsConflict should match their status codes")
+	}
+	if IsNotFound(fmt.Errorf("not an API error")) {
+		t.Error("IsNotFound should not match errors which are not API errors")
+	}
+}
@@ -0,0 +1,606 @@
+package qarnot
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Object stored by the fake S3 server
+type fakeObject struct {
+	data            []byte
+	etag            string
+	contentType     string
+	cacheControl    string
+	contentEncoding string
+	checksumSHA256  string
+	metadata        map[string]string
+	tags            map[string]string
+	// Sizes of the parts of objects uploaded in multiple parts
+	partSizes []int
+}
+
+// Minimal S3 server, only implementing what the SDK uses, with path style addressing
+type fakeS3 struct {
+	*httptest.Server
+	mu      sync.Mutex
+	buckets map[string]map[string]fakeObject
+	uploads map[string]map[int][]byte
+	// Headers of the multipart uploads, applied once completed
+	templates map[string]fakeObject
+	// Bucket and key of the multipart uploads
+	targets  map[string][2]string
+	pageSize int
+	// Requests for which this returns true fail with an internal error
+	fail func(r *http.Request) bool
+	// Number of requests received, by method
+	requests map[string]int
+}
+
+func newFakeS3(t *testing.T) *fakeS3 {
+	fake := &fakeS3{buckets: map[string]map[string]fakeObject{}, uploads: map[string]map[int][]byte{}, templates: map[string]fakeObject{}, targets: map[string][2]string{}, pageSize: 2, requests: map[string]int{}}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.handle))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeS3) put(bucket string, key string, data string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.buckets[bucket] == nil {
+		f.buckets[bucket] = map[string]fakeObject{}
+	}
+	sum := md5.Sum([]byte(data))
+	f.buckets[bucket][key] = fakeObject{data: []byte(data), etag: `"` + hex.EncodeToString(sum[:]) + `"`}
+}
+
+// Store an object as is, for the headers which cannot be set through `put`
+func (f *fakeS3) store(bucket string, key string, object fakeObject) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.buckets[bucket][key] = object
+}
+
+func (f *fakeS3) get(bucket string, key string) (fakeObject, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.buckets[bucket][key]
+	return object, ok
+}
+
+func (f *fakeS3) requestCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[method]
+}
+
+// Client using the fake S3 server as storage
+func (f *fakeS3) client(t *testing.T, apiUrl string) *Client {
+	qarnotConfig := QarnotConfig{
+		ApiUrl:      apiUrl,
+		ApiKey:      "xxx",
+		Email:       "test@example.org",
+		Version:     "v1",
+		StorageUrl:  f.URL,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 1},
+	}
+
+	client, err := NewClient(&qarnotConfig, WithS3Options(func(o *s3.Options) { o.UsePathStyle = true }))
+	if err != nil {
+		t.Fatalf("could not create a new client: %v", err)
+	}
+	return client
+}
+
+func (f *fakeS3) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests[r.Method]++
+	if f.fail != nil && f.fail(r) {
+		fakeS3Error(w, 500, "InternalError")
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	objects, ok := f.buckets[bucket]
+	if !ok {
+		fakeS3Error(w, 404, "NoSuchBucket")
+		return
+	}
+
+	if r.Method == "POST" && r.URL.Query().Has("delete") {
+		f.deleteObjects(w, r, objects)
+		return
+	}
+
+	if r.URL.Query().Has("tagging") {
+		f.tagging(w, r, objects, key)
+		return
+	}
+
+	if r.URL.Query().Has("uploads") || r.URL.Query().Has("uploadId") {
+		f.multipart(w, r, bucket, key)
+		return
+	}
+
+	switch {
+	case r.Method == "GET" && key == "":
+		f.list(w, r, objects)
+	case r.Method == "GET" || r.Method == "HEAD":
+		object, ok := objects[key]
+		if !ok {
+			fakeS3Error(w, 404, "NoSuchKey")
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && match != object.etag {
+			fakeS3Error(w, 412, "PreconditionFailed")
+			return
+		}
+		data := object.data
+		w.Header().Set("ETag", object.etag)
+		w.Header().Set("Last-Modified", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+		if object.contentType != "" {
+			w.Header().Set("Content-Type", object.contentType)
+		} else {
+			// The storage may omit the content type, this prevents the server from sniffing one
+			w.Header()["Content-Type"] = nil
+		}
+		if object.cacheControl != "" {
+			w.Header().Set("Cache-Control", object.cacheControl)
+		}
+		if object.contentEncoding != "" {
+			w.Header().Set("Content-Encoding", object.contentEncoding)
+		}
+		if object.checksumSHA256 != "" && r.Header.Get("X-Amz-Checksum-Mode") == "ENABLED" {
+			w.Header().Set("X-Amz-Checksum-Sha256", object.checksumSHA256)
+		}
+		for name, value := range object.metadata {
+			w.Header().Set("X-Amz-Meta-"+name, value)
+		}
+		status := 200
+		if number, _ := strconv.Atoi(r.URL.Query().Get("partNumber")); number > 0 && number <= len(object.partSizes) {
+			start := 0
+			for _, partSize := range object.partSizes[:number-1] {
+				start += partSize
+			}
+			end := start + object.partSizes[number-1] - 1
+			w.Header().Set("X-Amz-Mp-Parts-Count", strconv.Itoa(len(object.partSizes)))
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start, end, len(data)))
+			data, status = data[start:end+1], 206
+		} else if start, end, ok := parseFakeRange(r.Header.Get("Range"), len(data)); ok {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start, end, len(data)))
+			data, status = data[start:end+1], 206
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == "GET" {
+			w.Write(data)
+		}
+	case r.Method == "PUT" && r.Header.Get("X-Amz-Copy-Source") != "":
+		source, ok := f.copySource(r)
+		if !ok {
+			fakeS3Error(w, 404, "NoSuchKey")
+			return
+		}
+		object := source
+		if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+			object = objectFromHeaders(r, source.data)
+		}
+		objects[key] = object
+		w.Header().Set("Content-Type", "application/xml")
+		xml.NewEncoder(w).Encode(fakeCopyResult{XMLName: xml.Name{Local: "CopyObjectResult"}, ETag: object.etag, LastModified: "2024-01-01T00:00:00.000Z"})
+	case r.Method == "PUT":
+		data, _ := io.ReadAll(r.Body)
+		sum := md5.Sum(data)
+		if digest := r.Header.Get("Content-MD5"); digest != "" && digest != base64.StdEncoding.EncodeToString(sum[:]) {
+			fakeS3Error(w, 400, "BadDigest")
+			return
+		}
+		object := objectFromHeaders(r, data)
+		objects[key] = object
+		w.Header().Set("ETag", object.etag)
+	case r.Method == "DELETE" && key == "":
+		if len(objects) > 0 || f.hasUploads(bucket) {
+			fakeS3Error(w, 409, "BucketNotEmpty")
+			return
+		}
+		delete(f.buckets, bucket)
+		w.WriteHeader(204)
+	case r.Method == "DELETE":
+		delete(objects, key)
+		w.WriteHeader(204)
+	default:
+		fakeS3Error(w, 501, "NotImplemented")
+	}
+}
+
+type fakeInitiateResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Bucket   string
+	Key      string
+	UploadId string
+}
+
+type fakePart struct {
+	PartNumber int
+	ETag       string
+	Size       int `xml:",omitempty"`
+}
+
+type fakeListPartsResult struct {
+	XMLName     xml.Name `xml:"ListPartsResult"`
+	Bucket      string
+	Key         string
+	UploadId    string
+	IsTruncated bool
+	Part        []fakePart
+}
+
+type fakeCompleteResult struct {
+	XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+	Bucket  string
+	Key     string
+	ETag    string
+}
+
+// Object made of the data and the headers of a request
+func objectFromHeaders(r *http.Request, data []byte) fakeObject {
+	sum := md5.Sum(data)
+	object := fakeObject{
+		data:            data,
+		etag:            `"` + hex.EncodeToString(sum[:]) + `"`,
+		contentType:     r.Header.Get("Content-Type"),
+		cacheControl:    r.Header.Get("Cache-Control"),
+		contentEncoding: r.Header.Get("Content-Encoding"),
+		checksumSHA256:  r.Header.Get("X-Amz-Checksum-Sha256"),
+		metadata:        map[string]string{},
+	}
+	for name := range r.Header {
+		if strings.HasPrefix(name, "X-Amz-Meta-") {
+			object.metadata[strings.ToLower(strings.TrimPrefix(name, "X-Amz-Meta-"))] = r.Header.Get(name)
+		}
+	}
+	return object
+}
+
+type fakeCopyResult struct {
+	XMLName      xml.Name
+	ETag         string
+	LastModified string
+}
+
+// Object targeted by the copy source header of a request, restricted to the copy source range if any
+func (f *fakeS3) copySource(r *http.Request) (fakeObject, bool) {
+	source, _ := url.PathUnescape(strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/"))
+	bucket, key, _ := strings.Cut(source, "/")
+	object, ok := f.buckets[bucket][key]
+	if start, end, isRange := parseFakeRange(r.Header.Get("X-Amz-Copy-Source-Range"), len(object.data)); ok && isRange {
+		object.data = object.data[start : end+1]
+	}
+	return object, ok
+}
+
+func (f *fakeS3) multipart(w http.ResponseWriter, r *http.Request, bucket string, key string) {
+	query := r.URL.Query()
+	uploadId := query.Get("uploadId")
+	parts, ok := f.uploads[uploadId]
+	if !ok && !query.Has("uploads") {
+		fakeS3Error(w, 404, "NoSuchUpload")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	switch {
+	case r.Method == "GET" && query.Has("uploads"):
+		f.listUploads(w, r, bucket)
+	case r.Method == "POST" && query.Has("uploads"):
+		uploadId = fmt.Sprintf("upload-%v", len(f.uploads)+1)
+		f.uploads[uploadId] = map[int][]byte{}
+		f.templates[uploadId] = objectFromHeaders(r, nil)
+		f.targets[uploadId] = [2]string{bucket, key}
+		xml.NewEncoder(w).Encode(fakeInitiateResult{Bucket: bucket, Key: key, UploadId: uploadId})
+	case r.Method == "PUT" && r.Header.Get("X-Amz-Copy-Source") != "":
+		source, ok := f.copySource(r)
+		if !ok {
+			fakeS3Error(w, 404, "NoSuchKey")
+			return
+		}
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[number] = source.data
+		sum := md5.Sum(source.data)
+		xml.NewEncoder(w).Encode(fakeCopyResult{XMLName: xml.Name{Local: "CopyPartResult"}, ETag: `"` + hex.EncodeToString(sum[:]) + `"`, LastModified: "2024-01-01T00:00:00.000Z"})
+	case r.Method == "PUT":
+		data, _ := io.ReadAll(r.Body)
+		sum := md5.Sum(data)
+		if digest := r.Header.Get("Content-MD5"); digest != "" && digest != base64.StdEncoding.EncodeToString(sum[:]) {
+			fakeS3Error(w, 400, "BadDigest")
+			return
+		}
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[number] = data
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	case r.Method == "GET":
+		result := fakeListPartsResult{Bucket: bucket, Key: key, UploadId: uploadId}
+		for number, data := range parts {
+			sum := md5.Sum(data)
+			result.Part = append(result.Part, fakePart{PartNumber: number, ETag: `"` + hex.EncodeToString(sum[:]) + `"`, Size: len(data)})
+		}
+		slices.SortFunc(result.Part, func(a, b fakePart) int { return a.PartNumber - b.PartNumber })
+		xml.NewEncoder(w).Encode(result)
+	case r.Method == "POST":
+		var complete struct {
+			Part []fakePart
+		}
+		xml.NewDecoder(r.Body).Decode(&complete)
+
+		var data []byte
+		var partSizes []int
+		hash := md5.New()
+		for _, part := range complete.Part {
+			sum := md5.Sum(parts[part.PartNumber])
+			if `"`+hex.EncodeToString(sum[:])+`"` != part.ETag {
+				fakeS3Error(w, 400, "InvalidPart")
+				return
+			}
+			data = append(data, parts[part.PartNumber]...)
+			partSizes = append(partSizes, len(parts[part.PartNumber]))
+			hash.Write(sum[:])
+		}
+
+		etag := fmt.Sprintf(`"%v-%v"`, hex.EncodeToString(hash.Sum(nil)), len(complete.Part))
+		object := f.templates[uploadId]
+		object.data, object.etag, object.partSizes = data, etag, partSizes
+		f.buckets[bucket][key] = object
+		delete(f.uploads, uploadId)
+		xml.NewEncoder(w).Encode(fakeCompleteResult{Bucket: bucket, Key: key, ETag: etag})
+	case r.Method == "DELETE" && strings.HasPrefix(key, "locked/"):
+		fakeS3Error(w, 403, "AccessDenied")
+	case r.Method == "DELETE":
+		delete(f.uploads, uploadId)
+		w.WriteHeader(204)
+	}
+}
+
+type fakeUpload struct {
+	Key      string
+	UploadId string
+}
+
+type fakeListUploadsResult struct {
+	XMLName            xml.Name `xml:"ListMultipartUploadsResult"`
+	Bucket             string
+	IsTruncated        bool
+	NextKeyMarker      string `xml:",omitempty"`
+	NextUploadIdMarker string `xml:",omitempty"`
+	Upload             []fakeUpload
+}
+
+// Unfinished uploads prevent a bucket from being deleted, as their parts are kept
+func (f *fakeS3) hasUploads(bucket string) bool {
+	for uploadId := range f.uploads {
+		if f.targets[uploadId][0] == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// Lists the unfinished uploads of a bucket, ordered by upload id for simplicity
+func (f *fakeS3) listUploads(w http.ResponseWriter, r *http.Request, bucket string) {
+	var uploads []fakeUpload
+	for uploadId := range f.uploads {
+		if target := f.targets[uploadId]; target[0] == bucket && uploadId > r.URL.Query().Get("upload-id-marker") {
+			uploads = append(uploads, fakeUpload{Key: target[1], UploadId: uploadId})
+		}
+	}
+	slices.SortFunc(uploads, func(a, b fakeUpload) int { return strings.Compare(a.UploadId, b.UploadId) })
+
+	result := fakeListUploadsResult{Bucket: bucket, Upload: uploads}
+	if len(uploads) > f.pageSize {
+		last := uploads[f.pageSize-1]
+		result.Upload, result.IsTruncated = uploads[:f.pageSize], true
+		result.NextKeyMarker, result.NextUploadIdMarker = last.Key, last.UploadId
+	}
+	xml.NewEncoder(w).Encode(result)
+}
+
+type fakeDeleteResult struct {
+	XMLName xml.Name `xml:"DeleteResult"`
+	Deleted []fakeDeleted
+	Error   []fakeDeleteError
+}
+
+type fakeDeleted struct {
+	Key string
+}
+
+type fakeDeleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+// Keys starting with "locked/" cannot be deleted
+func (f *fakeS3) deleteObjects(w http.ResponseWriter, r *http.Request, objects map[string]fakeObject) {
+	f.requests["DELETEOBJECTS"]++
+
+	var request struct {
+		Object []struct {
+			Key string
+		}
+	}
+	xml.NewDecoder(r.Body).Decode(&request)
+
+	result := fakeDeleteResult{}
+	for _, object := range request.Object {
+		if strings.HasPrefix(object.Key, "locked/") {
+			result.Error = append(result.Error, fakeDeleteError{Key: object.Key, Code: "AccessDenied", Message: "Access Denied"})
+			continue
+		}
+		delete(objects, object.Key)
+		result.Deleted = append(result.Deleted, fakeDeleted{Key: object.Key})
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+type fakeTag struct {
+	Key   string
+	Value string
+}
+
+type fakeTagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	TagSet  struct {
+		Tag []fakeTag
+	}
+}
+
+func (f *fakeS3) tagging(w http.ResponseWriter, r *http.Request, objects map[string]fakeObject, key string) {
+	object, ok := objects[key]
+	if !ok {
+		fakeS3Error(w, 404, "NoSuchKey")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		names := []string{}
+		for name := range object.tags {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		tagging := fakeTagging{}
+		for _, name := range names {
+			tagging.TagSet.Tag = append(tagging.TagSet.Tag, fakeTag{Key: name, Value: object.tags[name]})
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		xml.NewEncoder(w).Encode(tagging)
+	case "PUT":
+		var tagging fakeTagging
+		xml.NewDecoder(r.Body).Decode(&tagging)
+		if len(tagging.TagSet.Tag) > 10 {
+			fakeS3Error(w, 400, "BadRequest")
+			return
+		}
+		object.tags = map[string]string{}
+		for _, tag := range tagging.TagSet.Tag {
+			object.tags[tag.Key] = tag.Value
+		}
+		objects[key] = object
+	default:
+		fakeS3Error(w, 501, "NotImplemented")
+	}
+}
+
+type fakeListContent struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int
+}
+
+type fakeCommonPrefix struct {
+	Prefix string
+}
+
+type fakeListResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Name                  string
+	Prefix                string
+	KeyCount              int
+	IsTruncated           bool
+	NextContinuationToken string `xml:",omitempty"`
+	Contents              []fakeListContent
+	CommonPrefixes        []fakeCommonPrefix
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request, objects map[string]fakeObject) {
+	query := r.URL.Query()
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	after := max(query.Get("start-after"), query.Get("continuation-token"))
+
+	pageSize := f.pageSize
+	if maxKeys, err := strconv.Atoi(query.Get("max-keys")); err == nil && maxKeys < pageSize {
+		pageSize = maxKeys
+	}
+
+	// Keys, or common prefixes when the key contains the delimiter after the prefix
+	var entries []string
+	for key := range objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if index := strings.Index(key[len(prefix):], delimiter); index >= 0 {
+				key = key[:len(prefix)+index+len(delimiter)]
+			}
+		}
+		if key > after && !slices.Contains(entries, key) {
+			entries = append(entries, key)
+		}
+	}
+	slices.Sort(entries)
+
+	result := fakeListResult{Name: strings.Trim(r.URL.Path, "/"), Prefix: prefix}
+	if len(entries) > pageSize {
+		entries = entries[:pageSize]
+		result.IsTruncated = true
+		result.NextContinuationToken = entries[len(entries)-1]
+	}
+	for _, entry := range entries {
+		object, ok := objects[entry]
+		if !ok || (delimiter != "" && strings.HasSuffix(entry, delimiter) && entry != prefix) {
+			result.CommonPrefixes = append(result.CommonPrefixes, fakeCommonPrefix{Prefix: entry})
+			continue
+		}
+		result.Contents = append(result.Contents, fakeListContent{
+			Key:          entry,
+			LastModified: "2024-01-01T00:00:00.000Z",
+			ETag:         object.etag,
+			Size:         len(object.data),
+		})
+	}
+	result.KeyCount = len(entries)
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+func parseFakeRange(header string, size int) (int, int, bool) {
+	var start, end int
+	if _, err := fmt.Sscanf(header, "bytes=%d-%d", &start, &end); err != nil {
+		if _, err := fmt.Sscanf(header, "bytes=%d-", &start); err != nil {
+			return 0, 0, false
+		}
+		end = size - 1
+	}
+	return start, min(end, size-1), true
+}
+
+func fakeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%v</Code><Message>%v</Message></Error>`, code, code)
+}
@@ -0,0 +1,117 @@
+package qarnot
+
+// Enum for the operator of a filter
+type FilterOperator string
+
+const (
+	EqualOperator              FilterOperator = "Equal"
+	NotEqualOperator           FilterOperator = "NotEqual"
+	GreaterThanOperator        FilterOperator = "GreaterThan"
+	GreaterThanOrEqualOperator FilterOperator = "GreaterThanOrEqual"
+	LessThanOperator           FilterOperator = "LessThan"
+	LessThanOrEqualOperator    FilterOperator = "LessThanOrEqual"
+	InOperator                 FilterOperator = "In"
+	NotInOperator              FilterOperator = "NotIn"
+	StartsWithOperator         FilterOperator = "StartsWith"
+	AndOperator                FilterOperator = "And"
+	OrOperator                 FilterOperator = "Or"
+)
+
+// Struct representing a filter used to search resources on the API side
+// Filters are built using the helpers below, and combined using `And` and `Or`
+//
+//	filter := qarnot.And(
+//		qarnot.Eq("State", "Success"),
+//		qarnot.In("Tags", "nightly", "weekly"),
+//		qarnot.Gte("CreationDate", time.Now().Add(-24*time.Hour)),
+//	)
+//
+// Fields are named after the fields of the resource in the API (`State`, `Tags`, `Profile`...)
+type Filter struct {
+	Operator FilterOperator `json:"operator"`
+	Field    string         `json:"field,omitempty"`
+	Value    any            `json:"value,omitempty"`
+	Filters  []Filter       `json:"filters,omitempty"`
+}
+
+// Match resources whose field is equal to the value
+func Eq(field string, value any) Filter {
+	return Filter{Operator: EqualOperator, Field: field, Value: value}
+}
+
+// Match resources whose field is not equal to the value
+func Ne(field string, value any) Filter {
+	return Filter{Operator: NotEqualOperator, Field: field, Value: value}
+}
+
+// Match resources whose field is greater than the value
+func Gt(field string, value any) Filter {
+	return Filter{Operator: GreaterThanOperator, Field: field, Value: value}
+}
+
+// Match resources whose field is greater than or equal to the value
+func Gte(field string, value any) Filter {
+	return Filter{Operator: GreaterThanOrEqualOperator, Field: field, Value: value}
+}
+
+// Match resources whose field is less than the value
+func Lt(field string, value any) Filter {
+	return Filter{Operator: LessThanOperator, Field: field, Value: value}
+}
+
+// Match resources whose field is less than or equal to the value
+func Lte(field string, value any) Filter {
+	return Filter{Operator: LessThanOrEqualOperator, Field: field, Value: value}
+}
+
+// Match resources whose field is one of the values
+// For fields holding a list (such as `Tags`), match resources having at least one of the values
+func In(field string, values ...any) Filter {
+	return Filter{Operator: InOperator, Field: field, Value: values}
+}
+
+// Match resources whose field is none of the values
+func NotIn(field string, values ...any) Filter {
+	return Filter{Operator: NotInOperator, Field: field, Value: values}
+}
+
+// Match resources whose field starts with the prefix
+func Prefix(field string, prefix string) Filter {
+	return Filter{Operator: StartsWithOperator, Field: field, Value: prefix}
+}
+
+// Match resources matching every given filter
+func And(filters ...Filter) Filter {
+	return Filter{Operator: AndOperator, Filters: filters}
+}
+
+// Match resources matching at least one of the given filters
+func Or(filters ...Filter) Filter {
+	return Filter{Operator: OrOperator, Filters: filters}
+}
+
+// Enum for the direction of a sort
+type SortDirection string
+
+const (
+	Ascending  SortDirection = "Ascending"
+	Descending SortDirection = "Descending"
+)
+
+// Struct representing the sort of the results of a search
+type Sort struct {
+	Field     string        `json:"field"`
+	Direction SortDirection `json:"direction"`
+}
+
+// Struct representing the options of a search
+type SearchOptions struct {
+	// Only the resources matching the filter are returned, every resource is returned when nil
+	Filter *Filter `json:"filter,omitempty"`
+	// Maximum number of resources to return, the API default is used when 0
+	MaximumResults int `json:"maximumResults,omitempty"`
+	// Sorts applied in order to the results
+	Sort []Sort `json:"sort,omitempty"`
+	// Only these fields are filled in the returned resources, every field is filled when empty
+	Fields []string `json:"fields,omitempty"`
+}
@@ -0,0 +1,44 @@
+package qarnot
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFilterSerialization(t *testing.T) {
+	creationDate, err := time.Parse(time.RFC3339, "2024-02-20T22:06:24Z")
+	if err != nil {
+		t.Errorf("could not parse time: %v", err)
+	}
+
+	filter := And(
+		Eq("State", "Success"),
+		Or(In("Tags", "nightly", "weekly"), Prefix("Name", "render-")),
+		Gte("CreationDate", creationDate),
+		Eq("AutoDeleteOnCompletion", false),
+	)
+
+	found, err := json.Marshal(SearchOptions{
+		Filter:         &filter,
+		MaximumResults: 10,
+		Sort:           []Sort{{Field: "CreationDate", Direction: Descending}},
+		Fields:         []string{"Uuid", "State"},
+	})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expected := `{"filter":{"operator":"And","filters":[` +
+		`{"operator":"Equal","field":"State","value":"Success"},` +
+		`{"operator":"Or","filters":[{"operator":"In","field":"Tags","value":["nightly","weekly"]},{"operator":"StartsWith","field":"Name","value":"render-"}]},` +
+		`{"operator":"GreaterThanOrEqual","field":"CreationDate","value":"2024-02-20T22:06:24Z"},` +
+		`{"operator":"Equal","field":"AutoDeleteOnCompletion","value":false}]},` +
+		`"maximumResults":10,"sort":[{"field":"CreationDate","direction":"Descending"}],"fields":["Uuid","State"]}`
+
+	if string(found) != expected {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expected)
+		t.Errorf("found    : %v", string(found))
+	}
+}
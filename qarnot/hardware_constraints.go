@@ -1,6 +1,7 @@
 package qarnot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -43,9 +44,14 @@ type HardwareConstraintsResponse struct {
 }
 
 func (c *Client) ListHardwareConstraints() (HardwareConstraintsResponse, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, "hardware-constraints")
+	return c.ListHardwareConstraintsWithContext(context.Background())
+}
+
+// Same as `ListHardwareConstraints`, but accepting a context to control cancellation and deadlines
+func (c *Client) ListHardwareConstraintsWithContext(ctx context.Context) (HardwareConstraintsResponse, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, "hardware-constraints")
 	if err != nil {
-		return HardwareConstraintsResponse{}, fmt.Errorf("could not retrieve list of hardware constraints due to the following error : %v", err)
+		return HardwareConstraintsResponse{}, fmt.Errorf("could not retrieve list of hardware constraints due to the following error : %w", err)
 	}
 
 	var response HardwareConstraintsResponse
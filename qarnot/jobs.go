@@ -1,6 +1,7 @@
 package qarnot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -12,8 +13,8 @@ type Job struct {
 	Name                        string   `json:"name"`
 	Shortname                   string   `json:"shortname"`
 	PoolUuid                    string   `json:"poolUuid"`
-	State                       string   `json:"state"`
-	PreviousState               string   `json:"previousState"`
+	State                       JobState `json:"state"`
+	PreviousState               JobState `json:"previousState"`
 	UseDependencies             bool     `json:"useDependencies"`
 	StateTransitionTime         string   `json:"stateTransitionTime"`
 	PreviousStateTransitionTime string   `json:"previousStateTransitionTime"`
@@ -41,7 +42,11 @@ type CreateJobResponse struct {
 }
 
 func (c *Client) ListJobs() ([]Job, error) {
-	resp, _, err := c.sendRequest("GET", []byte{}, nil, "jobs")
+	return c.ListJobsWithContext(context.Background())
+}
+
+func (c *Client) ListJobsWithContext(ctx context.Context) ([]Job, error) {
+	resp, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, "jobs")
 	if err != nil {
 		return []Job{}, err
 	}
@@ -55,7 +60,39 @@ func (c *Client) ListJobs() ([]Job, error) {
 	return jobs, nil
 }
 
+func (c *Client) SearchJobs(options SearchOptions) ([]Job, error) {
+	return c.SearchJobsWithContext(context.Background(), options)
+}
+
+func (c *Client) SearchJobsWithContext(ctx context.Context, options SearchOptions) ([]Job, error) {
+	payloadJson, err := json.Marshal(options)
+	if err != nil {
+		return []Job{}, helpers.FormatJsonMarshalError(err)
+	}
+
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, "jobs/search")
+	if err != nil {
+		return []Job{}, err
+	}
+
+	var jobs []Job
+	err = json.Unmarshal(data, &jobs)
+	if err != nil {
+		return nil, helpers.FormatJsonUnmarshalError(err)
+	}
+
+	return jobs, nil
+}
+
+func (c *Client) PaginateJobs(options PaginateOptions) *JobPager {
+	return newPager[Job](c, "jobs/paginate", "jobs", options)
+}
+
 func (c *Client) CreateJob(payload CreateJobPayload) (CreateJobResponse, error) {
+	return c.CreateJobWithContext(context.Background(), payload)
+}
+
+func (c *Client) CreateJobWithContext(ctx context.Context, payload CreateJobPayload) (CreateJobResponse, error) {
 	var response CreateJobResponse
 
 	payloadJson, err := json.Marshal(payload)
@@ -63,7 +100,7 @@ func (c *Client) CreateJob(payload CreateJobPayload) (CreateJobResponse, error)
 		return response, helpers.FormatJsonMarshalError(err)
 	}
 
-	data, _, err := c.sendRequest("POST", payloadJson, nil, "jobs")
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, "jobs")
 	if err != nil {
 		return response, err
 	}
@@ -77,6 +114,10 @@ func (c *Client) CreateJob(payload CreateJobPayload) (CreateJobResponse, error)
 }
 
 func (c *Client) DeleteJob(uuid string, force bool) error {
+	return c.DeleteJobWithContext(context.Background(), uuid, force)
+}
+
+func (c *Client) DeleteJobWithContext(ctx context.Context, uuid string, force bool) error {
 	var endpoint string
 	if force {
 		endpoint = fmt.Sprintf("jobs/%v?force=true", uuid)
@@ -84,7 +125,7 @@ func (c *Client) DeleteJob(uuid string, force bool) error {
 		endpoint = fmt.Sprintf("jobs/%v", uuid)
 	}
 
-	_, _, err := c.sendRequest("DELETE", []byte{}, nil, endpoint)
+	_, _, err := c.sendRequest(ctx, "DELETE", []byte{}, nil, endpoint)
 	if err != nil {
 		return err
 	}
@@ -92,7 +133,11 @@ func (c *Client) DeleteJob(uuid string, force bool) error {
 }
 
 func (c *Client) TerminateJob(uuid string) error {
-	_, _, err := c.sendRequest("POST", []byte{}, nil, fmt.Sprintf("jobs/%v/terminate", uuid))
+	return c.TerminateJobWithContext(context.Background(), uuid)
+}
+
+func (c *Client) TerminateJobWithContext(ctx context.Context, uuid string) error {
+	_, _, err := c.sendRequest(ctx, "POST", []byte{}, nil, fmt.Sprintf("jobs/%v/terminate", uuid))
 	if err != nil {
 		return err
 	}
@@ -100,7 +145,11 @@ func (c *Client) TerminateJob(uuid string) error {
 }
 
 func (c *Client) GetJobInfo(uuid string) (Job, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, fmt.Sprintf("jobs/%v", uuid))
+	return c.GetJobInfoWithContext(context.Background(), uuid)
+}
+
+func (c *Client) GetJobInfoWithContext(ctx context.Context, uuid string) (Job, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("jobs/%v", uuid))
 	if err != nil {
 		return Job{}, err
 	}
@@ -115,7 +164,11 @@ func (c *Client) GetJobInfo(uuid string) (Job, error) {
 }
 
 func (c *Client) ListJobTasks(uuid string) ([]Task, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, fmt.Sprintf("jobs/%v/tasks", uuid))
+	return c.ListJobTasksWithContext(context.Background(), uuid)
+}
+
+func (c *Client) ListJobTasksWithContext(ctx context.Context, uuid string) ([]Task, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("jobs/%v/tasks", uuid))
 	if err != nil {
 		return []Task{}, err
 	}
@@ -1 +1,130 @@
 package qarnot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSearchJobs(t *testing.T) {
+	expected := `[
+		{
+			"uuid": "job-1",
+			"name": "nightly",
+			"poolUuid": "pool-1",
+			"state": "Completed",
+			"tags": ["nightly"],
+			"creationDate": "2024-02-20T22:06:24Z"
+		}
+	]`
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var options SearchOptions
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &options); err != nil || r.URL.Path != "/v1/jobs/search" || r.Method != "POST" {
+				w.WriteHeader(404)
+				return
+			}
+
+			if options.Filter != nil && options.Filter.Field == "PoolUuid" && options.Filter.Value == "pool-1" {
+				fmt.Fprint(w, expected)
+			} else {
+				fmt.Fprint(w, "[]")
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	filter := Eq("PoolUuid", "pool-1")
+	jobs, err := client.SearchJobs(SearchOptions{Filter: &filter})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := []Job{
+		{
+			Uuid:         "job-1",
+			Name:         "nightly",
+			PoolUuid:     "pool-1",
+			State:        "Completed",
+			Tags:         []string{"nightly"},
+			CreationDate: "2024-02-20T22:06:24Z",
+		},
+	}
+	if !reflect.DeepEqual(jobs, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", jobs)
+	}
+}
+
+func TestPaginateJobs(t *testing.T) {
+	var filters []*Filter
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload paginatePayload
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &payload); err != nil || r.URL.Path != "/v1/jobs/paginate" || r.Method != "POST" {
+				w.WriteHeader(404)
+				return
+			}
+			filters = append(filters, payload.Filter)
+
+			if payload.Token == "" {
+				fmt.Fprint(w, `{"data": [{"uuid": "job-1"}], "nextToken": "next", "isTruncated": true}`)
+			} else {
+				fmt.Fprint(w, `{"data": [{"uuid": "job-2"}], "nextToken": "", "isTruncated": false}`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	filter := In("Tags", "nightly")
+	jobs, err := client.PaginateJobs(PaginateOptions{PageSize: 1, Filter: &filter}).All()
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := []Job{{Uuid: "job-1"}, {Uuid: "job-2"}}
+	if !reflect.DeepEqual(jobs, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", jobs)
+	}
+
+	if len(filters) != 2 || filters[0] == nil || filters[1] == nil || filters[1].Operator != InOperator {
+		t.Errorf("the filter should be sent with every page, found : %+v", filters)
+	}
+}
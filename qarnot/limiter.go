@@ -0,0 +1,189 @@
+package qarnot
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Struct describing the client side limits applied to the requests sent to the API and the S3 storage
+type Limits struct {
+	// Maximum number of requests per second, on average. 0 disables the rate limiter
+	RequestsPerSecond float64
+	// Number of requests which can be sent at once before being rate limited. Defaults to 1
+	Burst int
+	// Maximum number of requests waiting for a response at the same time. 0 disables the limit
+	MaxInFlight int
+}
+
+// Struct representing the time spent waiting on the client side limits
+type LimiterStats struct {
+	// Number of requests which went through the limiter
+	Requests int64
+	// Number of requests which had to wait before being sent
+	WaitedRequests int64
+	// Total and maximum time spent waiting by a single request
+	TotalWait time.Duration
+	MaxWait   time.Duration
+	// Number of requests currently waiting for a response
+	InFlight int64
+}
+
+type limiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	inFlight chan struct{}
+
+	requests       atomic.Int64
+	waitedRequests atomic.Int64
+	totalWait      atomic.Int64
+	maxWait        atomic.Int64
+	currentFlight  atomic.Int64
+}
+
+func newLimiter(limits *Limits) *limiter {
+	if limits == nil {
+		return nil
+	}
+
+	l := limiter{
+		rate:  limits.RequestsPerSecond,
+		burst: float64(max(limits.Burst, 1)),
+		last:  time.Now(),
+	}
+	l.tokens = l.burst
+
+	if limits.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limits.MaxInFlight)
+	}
+
+	return &l
+}
+
+// Reserve a token from the bucket, returning how long the caller has to wait before using it
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// Give back a reserved token which could not be used
+func (l *limiter) cancelReservation() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+1)
+}
+
+// Wait until a request can be sent. The returned function must be called once the response was handled
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+
+	if l.rate > 0 {
+		if delay := l.reserve(); delay > 0 {
+			if err := sleepWithContext(ctx, delay); err != nil {
+				l.cancelReservation()
+				return nil, err
+			}
+		}
+	}
+
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			if l.rate > 0 {
+				l.cancelReservation()
+			}
+			return nil, ctx.Err()
+		}
+	}
+
+	l.record(time.Since(start))
+	l.currentFlight.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.currentFlight.Add(-1)
+			if l.inFlight != nil {
+				<-l.inFlight
+			}
+		})
+	}, nil
+}
+
+func (l *limiter) record(wait time.Duration) {
+	l.requests.Add(1)
+
+	// Waits shorter than a millisecond are just the cost of going through the limiter
+	if wait < time.Millisecond {
+		return
+	}
+
+	l.waitedRequests.Add(1)
+	l.totalWait.Add(int64(wait))
+	for {
+		current := l.maxWait.Load()
+		if int64(wait) <= current || l.maxWait.CompareAndSwap(current, int64(wait)) {
+			break
+		}
+	}
+}
+
+func (l *limiter) stats() LimiterStats {
+	if l == nil {
+		return LimiterStats{}
+	}
+	return LimiterStats{
+		Requests:       l.requests.Load(),
+		WaitedRequests: l.waitedRequests.Load(),
+		TotalWait:      time.Duration(l.totalWait.Load()),
+		MaxWait:        time.Duration(l.maxWait.Load()),
+		InFlight:       l.currentFlight.Load(),
+	}
+}
+
+// Middleware applying the limiter to every attempt made by the S3 client
+func (l *limiter) s3Middleware(stack *middleware.Stack) error {
+	return stack.Finalize.Add(
+		middleware.FinalizeMiddlewareFunc(
+			"QarnotLimiter",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				release, err := l.acquire(ctx)
+				if err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+				}
+				defer release()
+
+				return next.HandleFinalize(ctx, in)
+			},
+		),
+		middleware.After,
+	)
+}
+
+// Will return the time spent waiting on the limits set in `QarnotConfig.Limits`
+func (c *Client) LimiterStats() LimiterStats {
+	return c.limiter.stats()
+}
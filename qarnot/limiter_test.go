@@ -0,0 +1,135 @@
+package qarnot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimitsMaxInFlight(t *testing.T) {
+	var current, highest atomic.Int32
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := current.Add(1)
+			defer current.Add(-1)
+			for {
+				h := highest.Load()
+				if n <= h || highest.CompareAndSwap(h, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprint(w, "{\"storage\": \"https://storage.qarnot.com\"}")
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+		Limits:     &Limits{MaxInFlight: 2},
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetSettings(); err != nil {
+				t.Errorf("err should be equal to nil: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if highest.Load() > 2 {
+		t.Errorf("no more than 2 requests should have been in flight, found %v", highest.Load())
+	}
+
+	stats := client.LimiterStats()
+	if stats.Requests != 10 || stats.WaitedRequests == 0 || stats.InFlight != 0 {
+		t.Errorf("unexpected limiter stats : %+v", stats)
+	}
+}
+
+func TestLimitsRequestsPerSecond(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "{\"storage\": \"https://storage.qarnot.com\"}")
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+		Limits:     &Limits{RequestsPerSecond: 50, Burst: 1},
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetSettings(); err != nil {
+			t.Errorf("err should be equal to nil: %v", err)
+		}
+	}
+
+	// The first request uses the burst, the 4 others wait for 20ms each
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("5 requests at 50 per second should take at least 80ms, took %v", elapsed)
+	}
+
+	if stats := client.LimiterStats(); stats.Requests != 5 || stats.WaitedRequests == 0 || stats.TotalWait <= 0 {
+		t.Errorf("unexpected limiter stats : %+v", stats)
+	}
+}
+
+func TestLimiterGivesBackTokenWhenCancelled(t *testing.T) {
+	l := newLimiter(&Limits{RequestsPerSecond: 1, Burst: 2, MaxInFlight: 1})
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+
+	// The second request gets a token, but gives up while waiting for the first one to complete
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = l.acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected : %v", context.DeadlineExceeded)
+		t.Errorf("found    : %v", err)
+	}
+	release()
+
+	// Its token was given back, so the next request does not wait for the bucket to refill
+	start := time.Now()
+	release, err = l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request should not have waited, took %v", elapsed)
+	}
+}
@@ -0,0 +1,191 @@
+package qarnot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Enum for the output stream a log line comes from
+type LogStream string
+
+const (
+	StdoutStream LogStream = "stdout"
+	StderrStream LogStream = "stderr"
+)
+
+// Struct representing a line of output of a task
+type LogLine struct {
+	// Instance the line comes from, -1 when the output of the whole task is streamed
+	InstanceId int
+	Stream     LogStream
+	// Text of the line, without the trailing newline
+	Text string
+	// Only set on the last value sent before the channel is closed, when streaming stopped on an error
+	Err error
+}
+
+// Struct representing the options of `StreamTaskLogs`
+type StreamLogsOptions struct {
+	// Streams to follow, both stdout and stderr when empty
+	Streams []LogStream
+	// Only follow the output of these instances, the output of the whole task is followed when empty
+	Instances []int
+	// Interval between two reads of the output, 2 seconds by default
+	PollInterval time.Duration
+}
+
+// A stream of a task, or of one of its instances, being followed
+type logSource struct {
+	instanceId int
+	stream     LogStream
+	pending    string
+}
+
+// Will read the output of a source written since the last read
+func (c *Client) readLastLogs(ctx context.Context, uuid string, source *logSource) (string, error) {
+	switch {
+	case source.instanceId < 0 && source.stream == StdoutStream:
+		return c.GetLastTaskStdoutWithContext(ctx, uuid)
+	case source.instanceId < 0:
+		return c.GetLastTaskStderrWithContext(ctx, uuid)
+	case source.stream == StdoutStream:
+		return c.GetLastTaskInstanceStdoutWithContext(ctx, uuid, source.instanceId)
+	default:
+		return c.GetLastTaskInstanceStderrWithContext(ctx, uuid, source.instanceId)
+	}
+}
+
+// Will follow the output of a task, until it reaches a terminal state
+// Lines are sent on the returned channel as they are read, which is closed once the task ended and
+// its whole output was sent, or once the context is cancelled
+// As it relies on the "since last read" endpoints, the output read here will not be returned
+// by `GetLastTaskStdout` and `GetLastTaskStderr` anymore
+//
+// Callers which may stop reading the channel before the task ended must cancel the context
+// once they are done, so that the goroutine following the output stops
+func (c *Client) StreamTaskLogs(ctx context.Context, uuid string, options StreamLogsOptions) (<-chan LogLine, error) {
+	streams := options.Streams
+	if len(streams) == 0 {
+		streams = []LogStream{StdoutStream, StderrStream}
+	}
+
+	instances := options.Instances
+	if len(instances) == 0 {
+		instances = []int{-1}
+	}
+
+	var sources []*logSource
+	for _, instanceId := range instances {
+		for _, stream := range streams {
+			if stream != StdoutStream && stream != StderrStream {
+				return nil, fmt.Errorf("could not stream task logs : unknown stream %v", stream)
+			}
+			sources = append(sources, &logSource{instanceId: instanceId, stream: stream})
+		}
+	}
+
+	interval := options.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	task, err := c.GetTaskInfoWithContext(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("could not stream task logs due to the following error : %w", err)
+	}
+
+	lines := make(chan LogLine)
+	go func() {
+		defer close(lines)
+
+		send := func(line LogLine) bool {
+			select {
+			case lines <- line:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			// The output is read after the state, so that nothing is missed once the task ended
+			ended := task.State.IsTerminal()
+
+			for _, source := range sources {
+				data, err := c.readLastLogs(ctx, uuid, source)
+				if err != nil {
+					send(LogLine{InstanceId: source.instanceId, Stream: source.stream, Err: fmt.Errorf("could not stream task logs due to the following error : %w", err)})
+					return
+				}
+
+				split := strings.Split(source.pending+data, "\n")
+				source.pending = split[len(split)-1]
+				if ended && source.pending != "" {
+					split = append(split, "")
+				}
+				for _, text := range split[:len(split)-1] {
+					if !send(LogLine{InstanceId: source.instanceId, Stream: source.stream, Text: strings.TrimSuffix(text, "\r")}) {
+						return
+					}
+				}
+			}
+
+			if ended {
+				return
+			}
+
+			if sleepWithContext(ctx, interval) != nil {
+				return
+			}
+
+			task, err = c.GetTaskInfoWithContext(ctx, uuid)
+			if err != nil {
+				send(LogLine{InstanceId: -1, Err: fmt.Errorf("could not stream task logs due to the following error : %w", err)})
+				return
+			}
+		}
+	}()
+
+	return lines, nil
+}
+
+// Reader over the lines sent by `StreamTaskLogs`, each followed by a newline
+// Returns `io.EOF` once the channel is closed, or the error of the stream if it stopped on one
+type LogReader struct {
+	lines  <-chan LogLine
+	buffer []byte
+	err    error
+}
+
+// Will create a reader over the lines of a log stream
+//
+//	lines, err := client.StreamTaskLogs(ctx, uuid, qarnot.StreamLogsOptions{})
+//	...
+//	io.Copy(os.Stdout, qarnot.NewLogReader(lines))
+func NewLogReader(lines <-chan LogLine) *LogReader {
+	return &LogReader{lines: lines}
+}
+
+func (r *LogReader) Read(p []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		line, ok := <-r.lines
+		if !ok {
+			r.err = io.EOF
+		} else if line.Err != nil {
+			r.err = line.Err
+		} else {
+			r.buffer = append(append(r.buffer, line.Text...), '\n')
+		}
+	}
+
+	n := copy(p, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
@@ -0,0 +1,172 @@
+package qarnot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamTaskLogs(t *testing.T) {
+	var mu sync.Mutex
+	states := []string{"Submitted", "FullyExecuting", "Success"}
+	outputs := map[string][]string{
+		"/v1/tasks/oktask/stdout":   {"hello\nwor", "ld\n", "bye"},
+		"/v1/tasks/oktask/stderr":   {"oops\r\n"},
+		"/v1/tasks/oktask/stdout/1": {"instance\n"},
+	}
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if r.URL.Path == "/v1/tasks/oktask" {
+				fmt.Fprintf(w, `{"uuid": "oktask", "state": %q}`, states[0])
+				if len(states) > 1 {
+					states = states[1:]
+				}
+			} else if chunks, ok := outputs[r.URL.Path]; ok && r.Method == "POST" {
+				chunk := ""
+				if len(chunks) > 0 {
+					chunk, outputs[r.URL.Path] = chunks[0], chunks[1:]
+				}
+				data, _ := json.Marshal(chunk)
+				w.Write(data)
+			} else if r.URL.Path == "/v1/tasks/oktask/stderr/1" {
+				w.WriteHeader(500)
+				fmt.Fprint(w, `{"message": "Internal error"}`)
+			} else {
+				w.WriteHeader(404)
+				fmt.Fprint(w, `{"message": "No such task: test"}`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:      srv.URL,
+		ApiKey:      "xxx",
+		Email:       "test@example.org",
+		Version:     "v1",
+		StorageUrl:  "http://fake.storage.qarnope.com",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 1},
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	lines, err := client.StreamTaskLogs(context.Background(), "oktask", StreamLogsOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	var found []LogLine
+	for line := range lines {
+		found = append(found, line)
+	}
+
+	expectedData := []LogLine{
+		{InstanceId: -1, Stream: StdoutStream, Text: "hello"},
+		{InstanceId: -1, Stream: StderrStream, Text: "oops"},
+		{InstanceId: -1, Stream: StdoutStream, Text: "world"},
+		{InstanceId: -1, Stream: StdoutStream, Text: "bye"},
+	}
+	if fmt.Sprint(found) != fmt.Sprint(expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedData)
+		t.Errorf("found    : %v", found)
+	}
+
+	// Per instance, as a reader, stopping on the error of stderr
+	lines, err = client.StreamTaskLogs(context.Background(), "oktask", StreamLogsOptions{Instances: []int{1}, PollInterval: time.Millisecond})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	data, err := io.ReadAll(NewLogReader(lines))
+	if string(data) != "instance\n" {
+		t.Error("different values.")
+		t.Errorf("expected : %q", "instance\n")
+		t.Errorf("found    : %q", string(data))
+	}
+
+	expectedErrorString := "could not stream task logs due to the following error : could not get last task instance stderr due to the following error : [HTTP 500] Internal error"
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+
+	_, err = client.StreamTaskLogs(context.Background(), "test", StreamLogsOptions{})
+	expectedErrorString = "could not stream task logs due to the following error : could not get task info due to the following error : [HTTP 404] No such task: test"
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+}
+
+// Will return the number of goroutines following the output of a task
+func countLogStreams() int {
+	buffer := make([]byte, 1<<20)
+	stacks := string(buffer[:runtime.Stack(buffer, true)])
+	return strings.Count(stacks, "qarnot.(*Client).StreamTaskLogs.func")
+}
+
+func TestStreamTaskLogsStoppedEarly(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/tasks/endless" {
+				fmt.Fprint(w, `{"uuid": "endless", "state": "FullyExecuting"}`)
+			} else {
+				fmt.Fprint(w, `"line\n"`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:      srv.URL,
+		ApiKey:      "xxx",
+		Email:       "test@example.org",
+		Version:     "v1",
+		StorageUrl:  "http://fake.storage.qarnope.com",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 1},
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines, err := client.StreamTaskLogs(ctx, "endless", StreamLogsOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+
+	// The consumer stops reading after the first line, while the task is still running
+	<-lines
+	if countLogStreams() != 1 {
+		t.Errorf("expected 1 goroutine following the output, found %v", countLogStreams())
+	}
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for countLogStreams() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if count := countLogStreams(); count != 0 {
+		t.Errorf("the goroutine following the output should have stopped, found %v", count)
+	}
+}
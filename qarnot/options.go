@@ -0,0 +1,91 @@
+package qarnot
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Option allowing to customize the client created by `NewClient`
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	userAgent  string
+	s3Options  []func(*s3.Options)
+	awsConfig  *aws.Config
+}
+
+func newClientOptions(options ...Option) clientOptions {
+	opts := clientOptions{
+		transport: http.DefaultTransport,
+		timeout:   15 * time.Second,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// Build the HTTP client used to communicate with the API
+func (o *clientOptions) buildHTTPClient() *http.Client {
+	if o.httpClient != nil {
+		return o.httpClient
+	}
+	return &http.Client{
+		Timeout:   o.timeout,
+		Transport: o.transport,
+	}
+}
+
+// Use the given HTTP client to communicate with the API
+// When set, `WithTransport` and `WithTimeout` have no effect on the requests sent to the API
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// Use the given transport for the requests sent to the API as well as the S3 storage
+// Useful to go through a proxy, to use mTLS or to trace requests
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.transport = transport
+	}
+}
+
+// Set the timeout of the requests sent to the API, 15 seconds by default
+// Requests sent to the S3 storage are not affected, since uploads and downloads may take a lot longer
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// Set the User-Agent header of the requests sent to the API
+// For the S3 storage, it is appended to the User-Agent of the AWS SDK
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// Apply additional options when creating the S3 client
+// They are applied last, so they can override anything set by the SDK
+func WithS3Options(options ...func(*s3.Options)) Option {
+	return func(o *clientOptions) {
+		o.s3Options = append(o.s3Options, options...)
+	}
+}
+
+// Use the given AWS configuration to create the S3 client, instead of loading the default one
+// Credentials are still set from the Qarnot configuration when the given configuration has none
+func WithAWSConfig(awsConfig aws.Config) Option {
+	return func(o *clientOptions) {
+		o.awsConfig = &awsConfig
+	}
+}
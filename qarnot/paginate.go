@@ -0,0 +1,144 @@
+package qarnot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redat00/qarnot-sdk-go/internal/helpers"
+)
+
+// Struct representing the options of a paginated listing
+type PaginateOptions struct {
+	// Maximum number of elements per page, the API default is used when 0
+	PageSize int
+	// Token of the page to start from, the listing starts from the beginning when empty
+	Token string
+	// Only the elements matching the filter are listed, every element is listed when nil
+	Filter *Filter
+}
+
+// Struct representing the payload sent to the paginate endpoints
+type paginatePayload struct {
+	Token          string  `json:"token,omitempty"`
+	MaximumResults int     `json:"maximumResults,omitempty"`
+	Filter         *Filter `json:"filter,omitempty"`
+}
+
+// Struct representing a page returned by the paginate endpoints
+type paginateResponse[T any] struct {
+	Data        []T    `json:"data"`
+	Token       string `json:"token"`
+	NextToken   string `json:"nextToken"`
+	IsTruncated bool   `json:"isTruncated"`
+}
+
+// Pager fetching the pages of a paginated listing one at a time, only when asked to
+//
+//	pager := client.PaginateTasks(qarnot.PaginateOptions{PageSize: 50})
+//	for pager.HasMorePages() {
+//		tasks, err := pager.NextPage()
+//		...
+//	}
+type Pager[T any] struct {
+	client   *Client
+	endpoint string
+	name     string
+	payload  paginatePayload
+	done     bool
+}
+
+// Pager over tasks, see `Client.PaginateTasks`
+type TaskPager = Pager[Task]
+
+// Pager over task summaries, see `Client.PaginateTaskSummaries`
+type TaskSummaryPager = Pager[TaskSummary]
+
+// Pager over jobs, see `Client.PaginateJobs`
+type JobPager = Pager[Job]
+
+func newPager[T any](client *Client, endpoint string, name string, options PaginateOptions) *Pager[T] {
+	return &Pager[T]{
+		client:   client,
+		endpoint: endpoint,
+		name:     name,
+		payload: paginatePayload{
+			Token:          options.Token,
+			MaximumResults: options.PageSize,
+			Filter:         options.Filter,
+		},
+	}
+}
+
+// Will return true until the last page was fetched
+func (p *Pager[T]) HasMorePages() bool {
+	return !p.done
+}
+
+// Token of the next page, which can be given back in `PaginateOptions` to resume a listing later
+func (p *Pager[T]) NextToken() string {
+	return p.payload.Token
+}
+
+// Will fetch the next page
+func (p *Pager[T]) NextPage() ([]T, error) {
+	return p.NextPageWithContext(context.Background())
+}
+
+// Same as `NextPage`, but accepting a context to control cancellation and deadlines
+func (p *Pager[T]) NextPageWithContext(ctx context.Context) ([]T, error) {
+	if p.done {
+		return []T{}, fmt.Errorf("could not paginate %v : no more pages", p.name)
+	}
+
+	payloadJson, err := json.Marshal(p.payload)
+	if err != nil {
+		return []T{}, helpers.FormatJsonMarshalError(err)
+	}
+
+	data, _, err := p.client.sendRequest(ctx, "POST", payloadJson, nil, p.endpoint)
+	if err != nil {
+		return []T{}, fmt.Errorf("could not paginate %v due to the following error : %w", p.name, err)
+	}
+
+	var page paginateResponse[T]
+	err = json.Unmarshal(data, &page)
+	if err != nil {
+		return []T{}, helpers.FormatJsonUnmarshalError(err)
+	}
+
+	p.payload.Token = page.NextToken
+	p.done = !page.IsTruncated || page.NextToken == ""
+
+	return page.Data, nil
+}
+
+// Will return every remaining element, fetching the pages one after the other
+func (p *Pager[T]) All() ([]T, error) {
+	return p.AllWithContext(context.Background())
+}
+
+// Same as `All`, but accepting a context to control cancellation and deadlines
+func (p *Pager[T]) AllWithContext(ctx context.Context) ([]T, error) {
+	var elements []T
+	for p.HasMorePages() {
+		page, err := p.NextPageWithContext(ctx)
+		if err != nil {
+			return elements, err
+		}
+		elements = append(elements, page...)
+	}
+	return elements, nil
+}
+
+// Will paginate over the tasks of the authenticated user
+// No request is sent until the first page is asked for
+func (c *Client) PaginateTasks(options PaginateOptions) *TaskPager {
+	return newPager[Task](c, "tasks/paginate", "tasks", options)
+}
+
+// Will paginate over the task summaries of the authenticated user
+// No request is sent until the first page is asked for
+func (c *Client) PaginateTaskSummaries(options PaginateOptions) *TaskSummaryPager {
+	return newPager[TaskSummary](c, "tasks/summaries/paginate", "task summaries", options)
+}
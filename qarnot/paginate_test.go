@@ -0,0 +1,168 @@
+package qarnot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPaginateTasks(t *testing.T) {
+	firstPage := `{
+		"data": [
+			{"uuid": "task-1", "name": "first"},
+			{"uuid": "task-2", "name": "second"}
+		],
+		"token": "",
+		"nextToken": "page-2",
+		"isTruncated": true
+	}`
+
+	secondPage := `{
+		"data": [
+			{"uuid": "task-3", "name": "third"}
+		],
+		"token": "page-2",
+		"nextToken": "",
+		"isTruncated": false
+	}`
+
+	var requests []paginatePayload
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload paginatePayload
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &payload); err != nil || r.URL.Path != "/v1/tasks/paginate" || r.Method != "POST" {
+				w.WriteHeader(404)
+				return
+			}
+			requests = append(requests, payload)
+
+			if payload.Token == "page-2" {
+				fmt.Fprint(w, secondPage)
+			} else {
+				fmt.Fprint(w, firstPage)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	pager := client.PaginateTasks(PaginateOptions{PageSize: 2})
+	if len(requests) != 0 {
+		t.Error("no request should be sent before asking for a page")
+	}
+
+	page, err := pager.NextPage()
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := []Task{{UUID: "task-1", Name: "first"}, {UUID: "task-2", Name: "second"}}
+	if !reflect.DeepEqual(page, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", page)
+	}
+
+	if !pager.HasMorePages() || pager.NextToken() != "page-2" {
+		t.Errorf("pager should have a next page with token page-2, found : %v", pager.NextToken())
+	}
+
+	page, err = pager.NextPage()
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData = []Task{{UUID: "task-3", Name: "third"}}
+	if !reflect.DeepEqual(page, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", page)
+	}
+
+	if pager.HasMorePages() {
+		t.Error("pager should not have more pages")
+	}
+
+	expectedRequests := []paginatePayload{{MaximumResults: 2}, {Token: "page-2", MaximumResults: 2}}
+	if !reflect.DeepEqual(requests, expectedRequests) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedRequests)
+		t.Errorf("found    : %+v", requests)
+	}
+
+	// Resuming from a token only fetches the remaining pages
+	tasks, err := client.PaginateTasks(PaginateOptions{PageSize: 2, Token: "page-2"}).All()
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if !reflect.DeepEqual(tasks, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", tasks)
+	}
+}
+
+func TestPaginateTaskSummaries(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/tasks/summaries/paginate" && r.Method == "POST" {
+				fmt.Fprint(w, `{"data": [{"uuid": "task-1", "state": "Success"}], "isTruncated": false}`)
+			} else {
+				w.WriteHeader(401)
+				fmt.Fprint(w, "{\"error\":{\"message\":\"Bad authentication token\",\"code\":401},\"data\":{}}")
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	summaries, err := client.PaginateTaskSummaries(PaginateOptions{}).All()
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := []TaskSummary{{Uuid: "task-1", State: "Success"}}
+	if !reflect.DeepEqual(summaries, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", summaries)
+	}
+
+	client.url = srv.URL + "/unknown"
+	expectedErrorString := "could not paginate tasks due to the following error : [HTTP 401] Bad authentication token"
+	_, err = client.PaginateTasks(PaginateOptions{}).NextPage()
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+}
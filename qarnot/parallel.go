@@ -0,0 +1,49 @@
+package qarnot
+
+import (
+	"context"
+	"sync"
+)
+
+// Will call `do` for every element, `concurrency` at a time, stopping at the first error
+func forEachParallel[T any](ctx context.Context, concurrency int, elements []T, do func(ctx context.Context, element T) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		firstErr  error
+		semaphore = make(chan struct{}, max(concurrency, 1))
+	)
+	for _, element := range elements {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			if err := do(ctx, element); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
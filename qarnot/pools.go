@@ -0,0 +1,230 @@
+package qarnot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redat00/qarnot-sdk-go/internal/helpers"
+)
+
+// Struct representing the elastic settings of a pool
+// When `IsElastic` is true, the number of slots of the pool is adjusted between `MinTotalSlots`
+// and `MaxTotalSlots`, depending on the number of tasks waiting to be executed
+type ElasticProperty struct {
+	IsElastic          bool    `json:"isElastic"`
+	MinTotalSlots      int     `json:"minTotalSlots"`
+	MaxTotalSlots      int     `json:"maxTotalSlots"`
+	MinIdleSlots       int     `json:"minIdleSlots"`
+	ResizePeriod       int     `json:"resizePeriod"`
+	RampResizeFactor   float64 `json:"rampResizeFactor"`
+	MinIdleTimeSeconds int     `json:"minIdleTimeSeconds"`
+}
+
+// Struct representing a pool with full details
+// Its status shares the same structure as the status of a task
+type Pool struct {
+	Errors                                         []QErrorPublic               `json:"errors,omitempty"`
+	ResourceBuckets                                []string                     `json:"resourceBuckets,omitempty"`
+	AdvancedResourceBuckets                        []TaskAdvancedResourceBucket `json:"advancedResourceBuckets,omitempty"`
+	Status                                         TaskStatus                   `json:"status,omitempty"`
+	Constants                                      []Constant                   `json:"constants,omitempty"`
+	SecretsAccessRights                            SecretsAccessRights          `json:"secretsAccessRights,omitempty"`
+	Tags                                           []string                     `json:"tags,omitempty"`
+	HardwareConstraints                            []HardwareConstraint         `json:"hardwareConstraints,omitempty"`
+	Labels                                         map[string]string            `json:"labels,omitempty"`
+	SchedulingType                                 SchedulingType               `json:"schedulingType,omitempty"`
+	Privileges                                     Privileges                   `json:"privileges,omitempty"`
+	DefaultRetrySettings                           RetrySettings                `json:"defaultRetrySettings,omitempty"`
+	ElasticProperty                                ElasticProperty              `json:"elasticProperty,omitempty"`
+	Scaling                                        Scaling                      `json:"scaling,omitempty"`
+	PreparationCommandLine                         string                       `json:"preparationCommandLine,omitempty"`
+	UUID                                           string                       `json:"uuid,omitempty"`
+	Name                                           string                       `json:"name,omitempty"`
+	Shortname                                      string                       `json:"shortname,omitempty"`
+	Profile                                        string                       `json:"profile,omitempty"`
+	RunningInstanceCount                           int                          `json:"runningInstanceCount,omitempty"`
+	RunningCoreCount                               int                          `json:"runningCoreCount,omitempty"`
+	ExecutionTime                                  string                       `json:"executionTime,omitempty"`
+	WallTime                                       string                       `json:"wallTime,omitempty"`
+	State                                          string                       `json:"state,omitempty"`
+	PreviousState                                  string                       `json:"previousState,omitempty"`
+	InstanceCount                                  int                          `json:"instanceCount,omitempty"`
+	QueuedOrRunningTaskInstancesCount              int                          `json:"queuedOrRunningTaskInstancesCount,omitempty"`
+	StateTransitionTime                            time.Time                    `json:"stateTransitionTime,omitempty"`
+	PreviousStateTransitionTime                    time.Time                    `json:"previousStateTransitionTime,omitempty"`
+	LastModified                                   time.Time                    `json:"lastModified,omitempty"`
+	CreationDate                                   time.Time                    `json:"creationDate,omitempty"`
+	EndDate                                        time.Time                    `json:"endDate,omitempty"`
+	TaskDefaultWaitForPoolResourcesSynchronization bool                         `json:"taskDefaultWaitForPoolResourcesSynchronization,omitempty"`
+	AutoDeleteOnCompletion                         bool                         `json:"autoDeleteOnCompletion,omitempty"`
+	CompletionTimeToLive                           string                       `json:"completionTimeToLive,omitempty"`
+	ForcedNetworkRules                             []ForcedNetworkRule          `json:"forcedNetworkRule,omitempty"`
+}
+
+// Struct representing the payload for the CreatePool method
+type CreatePoolPayload struct {
+	Name                                           string                        `json:"name"`
+	Shortname                                      string                        `json:"shortname,omitempty"`
+	Profile                                        string                        `json:"profile"`
+	InstanceCount                                  int                           `json:"instanceCount"`
+	ResourceBuckets                                []string                      `json:"resourceBuckets,omitempty"`
+	AdvancedResourceBuckets                        *[]TaskAdvancedResourceBucket `json:"advancedResourceBuckets,omitempty"`
+	Constants                                      *[]Constant                   `json:"constants,omitempty"`
+	ForcedConstants                                *[]ForcedConstant             `json:"forcedConstants,omitempty"`
+	Constraints                                    *[]map[string]string          `json:"constraints,omitempty"`
+	HardwareConstraints                            *[]HardwareConstraint         `json:"hardwareConstraints,omitempty"`
+	SecretsAccessRights                            *SecretsAccessRights          `json:"secretsAccessRights,omitempty"`
+	Tags                                           []string                      `json:"tags,omitempty"`
+	ElasticProperty                                *ElasticProperty              `json:"elasticProperty,omitempty"`
+	Scaling                                        *Scaling                      `json:"scaling,omitempty"`
+	PreparationCommandLine                         string                        `json:"preparationCommandLine,omitempty"`
+	TaskDefaultWaitForPoolResourcesSynchronization bool                          `json:"taskDefaultWaitForPoolResourcesSynchronization,omitempty"`
+	AutoDeleteOnCompletion                         bool                          `json:"autoDeleteOnCompletion,omitempty"`
+	CompletionTimeToLive                           string                        `json:"completionTimeToLive,omitempty"`
+	Labels                                         *map[string]string            `json:"labels,omitempty"`
+	SchedulingType                                 SchedulingType                `json:"schedulingType,omitempty"`
+	TargetedReservedMachineKey                     string                        `json:"targetedReservedMachineKey,omitempty"`
+	DefaultResourcesCacheTTLSec                    int                           `json:"defaultResourcesCacheTTLSec,omitempty"`
+	Privileges                                     *Privileges                   `json:"privileges,omitempty"`
+	DefaultRetrySettings                           *RetrySettings                `json:"defaultRetrySettings,omitempty"`
+	ForcedNetworkRules                             *[]ForcedNetworkRule          `json:"forcedNetworkRule,omitempty"`
+}
+
+// A struct representing the payload for the `UpdatePool` method
+type UpdatePoolPayload struct {
+	Constants       []Constant       `json:"constants,omitempty"`
+	Tags            []string         `json:"tags,omitempty"`
+	ElasticProperty *ElasticProperty `json:"elasticProperty,omitempty"`
+}
+
+// Will list the pools for the authenticated user
+// Optionally filter the results if any tags are provided
+func (c *Client) ListPools(tags ...string) ([]Pool, error) {
+	return c.ListPoolsWithContext(context.Background(), tags...)
+}
+
+// Same as `ListPools`, but accepting a context to control cancellation and deadlines
+func (c *Client) ListPoolsWithContext(ctx context.Context, tags ...string) ([]Pool, error) {
+	addQuery := func(req *http.Request) error {
+		query := req.URL.Query()
+		for _, tag := range tags {
+			query.Add("tag", tag)
+		}
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, "pools", addQuery)
+	if err != nil {
+		return []Pool{}, fmt.Errorf("could not list pools due to the following error : %w", err)
+	}
+
+	var pools []Pool
+	err = json.Unmarshal(data, &pools)
+	if err != nil {
+		return pools, helpers.FormatJsonUnmarshalError(err)
+	}
+
+	return pools, nil
+}
+
+// Will create a pool, based on a `CreatePoolPayload`
+// Returns a `UUIDResponse` struct, containing a UUID for the newly created pool
+func (c *Client) CreatePool(payload *CreatePoolPayload) (UUIDResponse, error) {
+	return c.CreatePoolWithContext(context.Background(), payload)
+}
+
+// Same as `CreatePool`, but accepting a context to control cancellation and deadlines
+func (c *Client) CreatePoolWithContext(ctx context.Context, payload *CreatePoolPayload) (UUIDResponse, error) {
+	var response UUIDResponse
+
+	payloadJson, err := json.Marshal(payload)
+	if err != nil {
+		return UUIDResponse{}, helpers.FormatJsonMarshalError(err)
+	}
+
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, "pools")
+	if err != nil {
+		return UUIDResponse{}, fmt.Errorf("could not create pool due to the following error : %w", err)
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return response, helpers.FormatJsonUnmarshalError(err)
+	}
+
+	return response, nil
+}
+
+// Will get the info for a pool
+func (c *Client) GetPoolInfo(uuid string) (Pool, error) {
+	return c.GetPoolInfoWithContext(context.Background(), uuid)
+}
+
+// Same as `GetPoolInfo`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetPoolInfoWithContext(ctx context.Context, uuid string) (Pool, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("pools/%v", uuid))
+	if err != nil {
+		return Pool{}, fmt.Errorf("could not get pool info due to the following error : %w", err)
+	}
+
+	var poolInfo Pool
+	err = json.Unmarshal(data, &poolInfo)
+	if err != nil {
+		return Pool{}, helpers.FormatJsonUnmarshalError(err)
+	}
+
+	return poolInfo, nil
+}
+
+// Will update the fields of a pool using the UUID as an argument, as well as a `UpdatePoolPayload` struct
+func (c *Client) UpdatePool(uuid string, payload UpdatePoolPayload) error {
+	return c.UpdatePoolWithContext(context.Background(), uuid, payload)
+}
+
+// Same as `UpdatePool`, but accepting a context to control cancellation and deadlines
+func (c *Client) UpdatePoolWithContext(ctx context.Context, uuid string, payload UpdatePoolPayload) error {
+	payloadJson, err := json.Marshal(payload)
+	if err != nil {
+		return helpers.FormatJsonMarshalError(err)
+	}
+
+	_, _, err = c.sendRequest(ctx, "PUT", payloadJson, nil, fmt.Sprintf("pools/%v", uuid))
+	if err != nil {
+		return fmt.Errorf("could not update pool due to the following error : %w", err)
+	}
+
+	return nil
+}
+
+// Will close a pool
+// The pool will stop accepting new tasks, and will be closed once its running tasks are completed
+func (c *Client) ClosePool(uuid string) error {
+	return c.ClosePoolWithContext(context.Background(), uuid)
+}
+
+// Same as `ClosePool`, but accepting a context to control cancellation and deadlines
+func (c *Client) ClosePoolWithContext(ctx context.Context, uuid string) error {
+	_, _, err := c.sendRequest(ctx, "POST", []byte{}, nil, fmt.Sprintf("pools/%v/close", uuid))
+	if err != nil {
+		return fmt.Errorf("could not close pool due to the following error : %w", err)
+	}
+	return nil
+}
+
+// Will delete a pool
+func (c *Client) DeletePool(uuid string) error {
+	return c.DeletePoolWithContext(context.Background(), uuid)
+}
+
+// Same as `DeletePool`, but accepting a context to control cancellation and deadlines
+func (c *Client) DeletePoolWithContext(ctx context.Context, uuid string) error {
+	_, _, err := c.sendRequest(ctx, "DELETE", []byte{}, nil, fmt.Sprintf("pools/%v", uuid))
+	if err != nil {
+		return fmt.Errorf("could not delete pool due to the following error : %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package qarnot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListPools(t *testing.T) {
+	expected := `[
+		{
+			"errors": [],
+			"resourceBuckets": [],
+			"constants": [
+			  {
+				"key": "DOCKER_REPO",
+				"value": "library/ubuntu"
+			  }
+			],
+			"tags": ["interactive"],
+			"elasticProperty": {
+			  "isElastic": true,
+			  "minTotalSlots": 1,
+			  "maxTotalSlots": 10,
+			  "minIdleSlots": 1,
+			  "resizePeriod": 90,
+			  "rampResizeFactor": 0.5,
+			  "minIdleTimeSeconds": 120
+			},
+			"schedulingType": "flex",
+			"uuid": "7f1e5a48-1e3c-4c2c-9c0d-8f1f4f1c0b1a",
+			"name": "interactive-pool",
+			"shortname": "interactive-pool",
+			"profile": "docker-batch",
+			"state": "FullyExecuting",
+			"previousState": "PartiallyExecuting",
+			"instanceCount": 2,
+			"creationDate": "2024-02-20T22:06:24Z"
+		}
+	]`
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/pools" && r.URL.Query().Get("tag") == "interactive" {
+				fmt.Fprint(w, expected)
+			} else {
+				fmt.Fprint(w, "[]")
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	pools, err := client.ListPools("interactive")
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	creationDate, err := time.Parse(time.RFC3339, "2024-02-20T22:06:24Z")
+	if err != nil {
+		t.Errorf("could not parse time: %v", err)
+	}
+
+	expectedData := []Pool{
+		{
+			Errors:          []QErrorPublic{},
+			ResourceBuckets: []string{},
+			Constants:       []Constant{{Key: "DOCKER_REPO", Value: "library/ubuntu"}},
+			Tags:            []string{"interactive"},
+			ElasticProperty: ElasticProperty{
+				IsElastic:          true,
+				MinTotalSlots:      1,
+				MaxTotalSlots:      10,
+				MinIdleSlots:       1,
+				ResizePeriod:       90,
+				RampResizeFactor:   0.5,
+				MinIdleTimeSeconds: 120,
+			},
+			SchedulingType: Flex,
+			UUID:           "7f1e5a48-1e3c-4c2c-9c0d-8f1f4f1c0b1a",
+			Name:           "interactive-pool",
+			Shortname:      "interactive-pool",
+			Profile:        "docker-batch",
+			State:          "FullyExecuting",
+			PreviousState:  "PartiallyExecuting",
+			InstanceCount:  2,
+			CreationDate:   creationDate,
+		},
+	}
+
+	if !reflect.DeepEqual(pools, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", pools)
+	}
+}
+
+func TestCreatePool(t *testing.T) {
+	expectedOk := `{
+		"uuid": "fakeuuid"
+	}`
+
+	expectedQuota := `{
+		"message": "Maximum number of pools reached"
+	}`
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload CreatePoolPayload
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &payload); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+
+			if r.URL.Path == "/v1/pools" && r.Method == "POST" && payload.Name == "okpool" {
+				fmt.Fprint(w, expectedOk)
+			} else {
+				w.WriteHeader(403)
+				fmt.Fprint(w, expectedQuota)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	uuid, err := client.CreatePool(&CreatePoolPayload{Name: "okpool", Profile: "docker-batch", InstanceCount: 2})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	testUuid := UUIDResponse{Uuid: "fakeuuid"}
+	if testUuid != uuid {
+		t.Error("different values.")
+		t.Errorf("expected : %v", testUuid)
+		t.Errorf("found    : %v", uuid)
+	}
+
+	expectedErrorString := "could not create pool due to the following error : [HTTP 403] Maximum number of pools reached"
+	_, err = client.CreatePool(&CreatePoolPayload{Name: "toomanypools", Profile: "docker-batch", InstanceCount: 2})
+	if err.Error() != expectedErrorString {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err.Error())
+	}
+}
+
+func TestGetPoolInfo(t *testing.T) {
+	expectedNotFound := `{
+		"message": "No such pool: notfoundpool"
+	}`
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/pools/okpool" && r.Method == "GET" {
+				fmt.Fprint(w, `{"uuid": "okpool", "name": "test", "state": "Closed"}`)
+			} else {
+				w.WriteHeader(404)
+				fmt.Fprint(w, expectedNotFound)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	pool, err := client.GetPoolInfo("okpool")
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := Pool{UUID: "okpool", Name: "test", State: "Closed"}
+	if !reflect.DeepEqual(pool, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", expectedData)
+		t.Errorf("found    : %+v", pool)
+	}
+
+	expectedErrorString := "could not get pool info due to the following error : [HTTP 404] No such pool: notfoundpool"
+	_, err = client.GetPoolInfo("notfoundpool")
+	if err.Error() != expectedErrorString {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err.Error())
+	}
+}
+
+func TestUpdateClosePool(t *testing.T) {
+	var updatePayload UpdatePoolPayload
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/pools/okpool" && r.Method == "PUT" {
+				body, _ := io.ReadAll(r.Body)
+				if err := json.Unmarshal(body, &updatePayload); err != nil {
+					w.WriteHeader(400)
+				}
+			} else if r.URL.Path == "/v1/pools/okpool/close" && r.Method == "POST" {
+				fmt.Fprint(w, nil)
+			} else if r.URL.Path == "/v1/pools/okpool" && r.Method == "DELETE" {
+				fmt.Fprint(w, nil)
+			} else {
+				w.WriteHeader(404)
+				fmt.Fprint(w, `{"message": "No such pool: test"}`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	payload := UpdatePoolPayload{
+		Tags:            []string{"updated"},
+		ElasticProperty: &ElasticProperty{IsElastic: true, MaxTotalSlots: 4},
+	}
+	if err = client.UpdatePool("okpool", payload); err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if !reflect.DeepEqual(updatePayload, payload) {
+		t.Error("different values.")
+		t.Errorf("expected : %+v", payload)
+		t.Errorf("found    : %+v", updatePayload)
+	}
+
+	if err = client.ClosePool("okpool"); err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	if err = client.DeletePool("okpool"); err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedErrorString := "could not close pool due to the following error : [HTTP 404] No such pool: test"
+	err = client.ClosePool("test")
+	if err.Error() != expectedErrorString {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err.Error())
+	}
+
+	expectedErrorString = "could not delete pool due to the following error : [HTTP 404] No such pool: test"
+	err = client.DeletePool("test")
+	if err.Error() != expectedErrorString {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err.Error())
+	}
+}
@@ -0,0 +1,122 @@
+package qarnot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// Presigned URLs cannot be valid for more than 7 days
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+// Struct representing a presigned request, which can be done without any credentials until it expires
+type PresignedRequest struct {
+	Method string
+	URL    string
+	// Headers included in the signature, which must be sent along with the request
+	SignedHeaders http.Header
+}
+
+func checkPresignExpiry(expiry time.Duration) error {
+	if expiry <= 0 || expiry > MaxPresignExpiry {
+		return fmt.Errorf("expiry must be between 0 and %v, found %v", MaxPresignExpiry, expiry)
+	}
+	return nil
+}
+
+func presignedRequestFrom(request *v4.PresignedHTTPRequest) PresignedRequest {
+	headers := request.SignedHeader
+	if headers == nil {
+		headers = http.Header{}
+	}
+	return PresignedRequest{Method: request.Method, URL: request.URL, SignedHeaders: headers}
+}
+
+// The S3 client drops the content type of requests without a body before presigning them,
+// this sets it back so that it is part of the signature
+func withSignedContentType(contentType string) func(*s3.PresignOptions) {
+	return func(o *s3.PresignOptions) {
+		o.ClientOptions = append(o.ClientOptions, func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+				return stack.Build.Add(
+					middleware.BuildMiddlewareFunc(
+						"QarnotSignedContentType",
+						func(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+							if request, ok := in.Request.(*smithyhttp.Request); ok {
+								request.Header.Set("Content-Type", contentType)
+							}
+							return next.HandleBuild(ctx, in)
+						},
+					),
+					middleware.After,
+				)
+			})
+		})
+	}
+}
+
+// Presign a request downloading an object, valid for `expiry`
+func (c *Client) PresignGetObject(bucketName string, key string, expiry time.Duration) (PresignedRequest, error) {
+	return c.PresignGetObjectWithContext(context.Background(), bucketName, key, expiry)
+}
+
+// Same as `PresignGetObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) PresignGetObjectWithContext(ctx context.Context, bucketName string, key string, expiry time.Duration) (PresignedRequest, error) {
+	err := checkPresignExpiry(expiry)
+	if err != nil {
+		return PresignedRequest{}, fmt.Errorf("could not presign object (%v/%v) due to the following error : %w", bucketName, key, err)
+	}
+
+	request, err := s3.NewPresignClient(c.s3).PresignGetObject(
+		ctx,
+		&s3.GetObjectInput{
+			Bucket: &bucketName,
+			Key:    &key,
+		},
+		s3.WithPresignExpires(expiry),
+	)
+	if err != nil {
+		return PresignedRequest{}, fmt.Errorf("could not presign object (%v/%v) due to the following error : %w", bucketName, key, err)
+	}
+
+	return presignedRequestFrom(request), nil
+}
+
+// Presign a request uploading an object, valid for `expiry`
+// The content type is part of the signature when given, so the upload must be done with the same one
+func (c *Client) PresignPutObject(bucketName string, key string, expiry time.Duration, contentType string) (PresignedRequest, error) {
+	return c.PresignPutObjectWithContext(context.Background(), bucketName, key, expiry, contentType)
+}
+
+// Same as `PresignPutObject`, but accepting a context to control cancellation and deadlines
+func (c *Client) PresignPutObjectWithContext(ctx context.Context, bucketName string, key string, expiry time.Duration, contentType string) (PresignedRequest, error) {
+	err := checkPresignExpiry(expiry)
+	if err != nil {
+		return PresignedRequest{}, fmt.Errorf("could not presign object (%v/%v) due to the following error : %w", bucketName, key, err)
+	}
+
+	presignOptions := []func(*s3.PresignOptions){s3.WithPresignExpires(expiry)}
+	if contentType != "" {
+		presignOptions = append(presignOptions, withSignedContentType(contentType))
+	}
+
+	request, err := s3.NewPresignClient(c.s3).PresignPutObject(
+		ctx,
+		&s3.PutObjectInput{
+			Bucket: &bucketName,
+			Key:    &key,
+		},
+		presignOptions...,
+	)
+	if err != nil {
+		return PresignedRequest{}, fmt.Errorf("could not presign object (%v/%v) due to the following error : %w", bucketName, key, err)
+	}
+
+	return presignedRequestFrom(request), nil
+}
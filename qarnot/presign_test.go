@@ -0,0 +1,75 @@
+package qarnot
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPresignGetObject(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("results", "frames/0001.png", "frame")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	request, err := client.PresignGetObject("results", "frames/0001.png", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+	if request.Method != http.MethodGet || !strings.Contains(request.URL, "X-Amz-Expires=900") || !strings.Contains(request.URL, "X-Amz-Signature=") {
+		t.Errorf("unexpected presigned request : %+v", request)
+	}
+
+	// The request can be done without the SDK
+	response, err := http.Get(request.URL)
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+	defer response.Body.Close()
+	data, _ := io.ReadAll(response.Body)
+	if string(data) != "frame" {
+		t.Error("different values.")
+		t.Errorf("expected : %v", "frame")
+		t.Errorf("found    : %v", string(data))
+	}
+
+	_, err = client.PresignGetObject("results", "frames/0001.png", 8*24*time.Hour)
+	expectedErrorString := "could not presign object (results/frames/0001.png) due to the following error : expiry must be between 0 and 168h0m0s, found 192h0m0s"
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+}
+
+func TestPresignPutObject(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("inputs", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	request, err := client.PresignPutObject("inputs", "scene.blend", time.Hour, "application/x-blender")
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+	if request.Method != http.MethodPut || request.SignedHeaders.Get("Content-Type") != "application/x-blender" {
+		t.Errorf("unexpected presigned request : %+v", request)
+	}
+
+	upload, _ := http.NewRequest(request.Method, request.URL, strings.NewReader("scene"))
+	for name, values := range request.SignedHeaders {
+		if !strings.EqualFold(name, "Host") {
+			upload.Header[name] = values
+		}
+	}
+	response, err := http.DefaultClient.Do(upload)
+	if err != nil {
+		t.Fatalf("err should be equal to nil: %v", err)
+	}
+	response.Body.Close()
+
+	object, _ := storage.get("inputs", "scene.blend")
+	if string(object.data) != "scene" || object.contentType != "application/x-blender" {
+		t.Errorf("unexpected object : %+v", object)
+	}
+}
@@ -1,6 +1,7 @@
 package qarnot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -26,9 +27,14 @@ type ProfileDetails struct {
 }
 
 func (c *Client) ListProfiles() ([]string, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, "profiles")
+	return c.ListProfilesWithContext(context.Background())
+}
+
+// Same as `ListProfiles`, but accepting a context to control cancellation and deadlines
+func (c *Client) ListProfilesWithContext(ctx context.Context) ([]string, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, "profiles")
 	if err != nil {
-		return []string{}, fmt.Errorf("could not get the list of profiles due to the following error : %v", err)
+		return []string{}, fmt.Errorf("could not get the list of profiles due to the following error : %w", err)
 	}
 
 	var profiles []string
@@ -41,9 +47,14 @@ func (c *Client) ListProfiles() ([]string, error) {
 }
 
 func (c *Client) GetProfileDetails(name string) (ProfileDetails, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, fmt.Sprintf("profiles/%v", name))
+	return c.GetProfileDetailsWithContext(context.Background(), name)
+}
+
+// Same as `GetProfileDetails`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetProfileDetailsWithContext(ctx context.Context, name string) (ProfileDetails, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("profiles/%v", name))
 	if err != nil {
-		return ProfileDetails{}, fmt.Errorf("could not get profiles details due to the following error : %v", err)
+		return ProfileDetails{}, fmt.Errorf("could not get profiles details due to the following error : %w", err)
 	}
 
 	var profileDetails ProfileDetails
@@ -0,0 +1,146 @@
+package qarnot
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Struct representing the options of `DownloadTaskResults`
+type DownloadResultsOptions struct {
+	// Only download the results matching at least one of these globs, every result is downloaded when empty
+	// Globs use the syntax of `path.Match`, and are matched against the path of the result relative to
+	// the results prefix of the task, as well as against its base name (so that "*.log" matches "logs/0.log")
+	Include []string
+	// Do not download the results matching any of these globs, even if they are included
+	Exclude []string
+	// Also filter the results using the `ResultsWhitelist` and `ResultsBlacklist` regular expressions of the task
+	UseTaskFilters bool
+	// Number of results downloaded at the same time, 4 by default
+	Concurrency int
+}
+
+// Will return true if the key, relative to the results prefix, matches one of the globs
+func matchesAnyGlob(globs []string, key string) (bool, error) {
+	for _, glob := range globs {
+		for _, name := range []string{key, path.Base(key)} {
+			matched, err := path.Match(glob, name)
+			if err != nil {
+				return false, fmt.Errorf("invalid glob %v : %w", glob, err)
+			}
+			if matched {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Will download the results of a task from its result bucket to a local directory
+// Results are downloaded in parallel, and their size and checksum are checked once downloaded
+// No other download is started once one of them failed
+// Returns the local paths of the downloaded results
+func (c *Client) DownloadTaskResults(uuid string, localDir string, options DownloadResultsOptions) ([]string, error) {
+	return c.DownloadTaskResultsWithContext(context.Background(), uuid, localDir, options)
+}
+
+// Same as `DownloadTaskResults`, but accepting a context to control cancellation and deadlines
+func (c *Client) DownloadTaskResultsWithContext(ctx context.Context, uuid string, localDir string, options DownloadResultsOptions) ([]string, error) {
+	task, err := c.GetTaskInfoWithContext(ctx, uuid)
+	if err != nil {
+		return []string{}, fmt.Errorf("could not download task results due to the following error : %w", err)
+	}
+	if task.ResultBucket == "" {
+		return []string{}, fmt.Errorf("could not download task results : task %v has no result bucket", uuid)
+	}
+
+	var whitelist, blacklist *regexp.Regexp
+	if options.UseTaskFilters && task.ResultsWhitelist != "" {
+		whitelist, err = regexp.Compile(task.ResultsWhitelist)
+		if err != nil {
+			return []string{}, fmt.Errorf("could not download task results due to the following error : %w", err)
+		}
+	}
+	if options.UseTaskFilters && task.ResultsBlacklist != "" {
+		blacklist, err = regexp.Compile(task.ResultsBlacklist)
+		if err != nil {
+			return []string{}, fmt.Errorf("could not download task results due to the following error : %w", err)
+		}
+	}
+
+	listing, err := c.ListBucketObjectsWithContext(ctx, task.ResultBucket, ListObjectsOptions{Prefix: task.ResultsBucketPrefix})
+	if err != nil {
+		return []string{}, fmt.Errorf("could not download task results due to the following error : %w", err)
+	}
+
+	root, err := filepath.Abs(localDir)
+	if err != nil {
+		return []string{}, fmt.Errorf("could not download task results due to the following error : %w", err)
+	}
+
+	type download struct {
+		key       string
+		localPath string
+	}
+
+	var downloads []download
+	for _, object := range listing.Objects {
+		relative := strings.TrimPrefix(strings.TrimPrefix(object.Name, task.ResultsBucketPrefix), "/")
+		// Keys ending with a slash only represent directories
+		if relative == "" || strings.HasSuffix(relative, "/") {
+			continue
+		}
+
+		included := len(options.Include) == 0
+		if !included {
+			included, err = matchesAnyGlob(options.Include, relative)
+			if err != nil {
+				return []string{}, fmt.Errorf("could not download task results due to the following error : %w", err)
+			}
+		}
+		excluded, err := matchesAnyGlob(options.Exclude, relative)
+		if err != nil {
+			return []string{}, fmt.Errorf("could not download task results due to the following error : %w", err)
+		}
+		if !included || excluded || (whitelist != nil && !whitelist.MatchString(relative)) || (blacklist != nil && blacklist.MatchString(relative)) {
+			continue
+		}
+
+		localPath := filepath.Join(root, filepath.FromSlash(relative))
+		if !strings.HasPrefix(localPath, root+string(filepath.Separator)) {
+			return []string{}, fmt.Errorf("could not download task results : %v would be written outside of %v", object.Name, localDir)
+		}
+		downloads = append(downloads, download{key: object.Name, localPath: localPath})
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var mu sync.Mutex
+	localPaths := []string{}
+	err = forEachParallel(ctx, concurrency, downloads, func(ctx context.Context, d download) error {
+		_, err := c.downloadObject(ctx, task.ResultBucket, d.key, d.localPath)
+		if err != nil {
+			return fmt.Errorf("could not download task result (%v) due to the following error : %w", d.key, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		localPaths = append(localPaths, d.localPath)
+		return nil
+	})
+	slices.Sort(localPaths)
+
+	if err != nil {
+		return localPaths, err
+	}
+
+	return localPaths, nil
+}
@@ -0,0 +1,107 @@
+package qarnot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDownloadTaskResults(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("results", "task-1/out.txt", "output")
+	storage.put("results", "task-1/logs/0.log", "log 0")
+	storage.put("results", "task-1/logs/1.log", "log 1")
+	storage.put("results", "task-1/logs/", "")
+	storage.put("results", "task-2/out.txt", "other task")
+	storage.put("corrupted", "out.txt", "output")
+	storage.buckets["corrupted"]["out.txt"] = fakeObject{data: []byte("output"), etag: `"00000000000000000000000000000000"`}
+	storage.put("corrupted", "sequential/0.txt", "output")
+	storage.store("corrupted", "sequential/0.txt", fakeObject{data: []byte("output"), etag: `"00000000000000000000000000000000"`})
+	storage.put("corrupted", "sequential/1.txt", "output")
+	storage.put("corrupted", "sequential/2.txt", "output")
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/tasks/oktask" {
+				fmt.Fprint(w, `{"uuid": "oktask", "resultBucket": "results", "resultsBucketPrefix": "task-1/", "resultsWhitelist": ".*\\.txt$"}`)
+			} else if r.URL.Path == "/v1/tasks/corruptedtask" {
+				fmt.Fprint(w, `{"uuid": "corruptedtask", "resultBucket": "corrupted", "resultsWhitelist": "^out"}`)
+			} else if r.URL.Path == "/v1/tasks/sequentialtask" {
+				fmt.Fprint(w, `{"uuid": "sequentialtask", "resultBucket": "corrupted", "resultsBucketPrefix": "sequential/"}`)
+			} else {
+				w.WriteHeader(404)
+				fmt.Fprint(w, `{"message": "No such task: test"}`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	client := storage.client(t, srv.URL)
+
+	for _, testCase := range []struct {
+		options  DownloadResultsOptions
+		expected []string
+	}{
+		{DownloadResultsOptions{}, []string{"logs/0.log", "logs/1.log", "out.txt"}},
+		{DownloadResultsOptions{Include: []string{"logs/*"}, Exclude: []string{"1.log"}}, []string{"logs/0.log"}},
+		{DownloadResultsOptions{UseTaskFilters: true, Concurrency: 1}, []string{"out.txt"}},
+	} {
+		localDir := t.TempDir()
+		localPaths, err := client.DownloadTaskResults("oktask", localDir, testCase.options)
+		if err != nil {
+			t.Errorf("err should be equal to nil: %v", err)
+		}
+
+		var found []string
+		for _, localPath := range localPaths {
+			relative, _ := filepath.Rel(localDir, localPath)
+			found = append(found, filepath.ToSlash(relative))
+		}
+		if strings.Join(found, ",") != strings.Join(testCase.expected, ",") {
+			t.Error("different values.")
+			t.Errorf("expected : %v", testCase.expected)
+			t.Errorf("found    : %v", found)
+		}
+	}
+
+	localDir := t.TempDir()
+	_, err := client.DownloadTaskResults("oktask", localDir, DownloadResultsOptions{})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(localDir, "logs", "1.log"))
+	if err != nil || string(data) != "log 1" {
+		t.Errorf("unexpected content for logs/1.log : %q (%v)", data, err)
+	}
+
+	_, err = client.DownloadTaskResults("corruptedtask", t.TempDir(), DownloadResultsOptions{UseTaskFilters: true})
+	expectedErrorString := "could not download task result (out.txt) due to the following error : checksum mismatch for out.txt, expected 00000000000000000000000000000000 but computed 78e6221f6393d1356681db398f14ce6d"
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+
+	// No other result is downloaded once one of them failed
+	getsBefore := storage.requestCount("GET")
+	localPaths, err := client.DownloadTaskResults("sequentialtask", t.TempDir(), DownloadResultsOptions{Concurrency: 1})
+	if err == nil || len(localPaths) != 0 {
+		t.Errorf("the download should fail on the first result, found %v (%v)", localPaths, err)
+	}
+	// Two requests to list the results, and one for the corrupted result
+	if gets := storage.requestCount("GET") - getsBefore; gets != 3 {
+		t.Errorf("expected 3 requests, found %v", gets)
+	}
+
+	_, err = client.DownloadTaskResults("test", t.TempDir(), DownloadResultsOptions{})
+	expectedErrorString = "could not download task results due to the following error : could not get task info due to the following error : [HTTP 404] No such task: test"
+	if err == nil || err.Error() != expectedErrorString {
+		t.Error("different error.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err)
+	}
+}
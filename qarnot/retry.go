@@ -0,0 +1,169 @@
+package qarnot
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// Struct describing how failed requests should be retried, both for the API and the S3 storage
+//
+// The delay before a retry is `BaseBackoff * 2^(attempt-1)`, capped to `MaxBackoff`, of which
+// a `Jitter` fraction is randomised. When the server answers with a `Retry-After` header,
+// its value is used instead (still capped to `MaxBackoff`)
+type RetryPolicy struct {
+	// Total number of attempts, including the first one. 0 or 1 disables retries
+	MaxAttempts int
+	// Default to the values of `DefaultRetryPolicy` when not set
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Between 0 and 1, the fraction of the backoff which is randomised
+	Jitter float64
+	// By default, non idempotent requests (POST) are only retried when we know the API did not
+	// process them : the connection could not be established, or the API answered with
+	// a 429 or a 503. Setting this to true retries them on every retryable error
+	RetryNonIdempotent bool
+}
+
+// Retry policy used when you want retries but don't want to tune them
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseBackoff: 500 * time.Millisecond,
+	MaxBackoff:  20 * time.Second,
+	Jitter:      0.5,
+}
+
+// Status codes which are considered transient
+var retryableStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Compute the delay to wait before the given retry attempt (starting at 1)
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+
+	if retryAfter > 0 {
+		return min(retryAfter, maxBackoff)
+	}
+
+	baseBackoff := p.BaseBackoff
+	if baseBackoff <= 0 {
+		baseBackoff = DefaultRetryPolicy.BaseBackoff
+	}
+
+	delay := float64(baseBackoff) * math.Pow(2, float64(attempt-1))
+	delay = min(delay, float64(maxBackoff))
+
+	jitter := min(max(p.Jitter, 0), 1)
+	delay = delay*(1-jitter) + delay*jitter*rand.Float64()
+
+	return time.Duration(delay)
+}
+
+// Will return true if the request can be sent again after the given response or error
+func (p *RetryPolicy) shouldRetry(method string, statusCode int, err error) bool {
+	idempotent := p.RetryNonIdempotent || method != http.MethodPost
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if idempotent {
+			return true
+		}
+		return isConnectionRefused(err)
+	}
+
+	if !retryableStatusCodes[statusCode] {
+		return false
+	}
+	return idempotent || statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
+// Will return true if the error happened before the request could be sent
+func isConnectionRefused(err error) bool {
+	var opError *net.OpError
+	if errors.As(err, &opError) && opError.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// Parse the `Retry-After` header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
+
+// Wait for the given delay, or until the context is done
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Implements the `retry.BackoffDelayer` interface so the S3 client uses the same backoff
+type s3Backoff struct {
+	policy *RetryPolicy
+}
+
+func (b s3Backoff) BackoffDelay(attempt int, err error) (time.Duration, error) {
+	var retryAfter time.Duration
+	var responseError *awshttp.ResponseError
+	if errors.As(err, &responseError) && responseError.Response != nil {
+		retryAfter = parseRetryAfter(responseError.Response.Header)
+	}
+	return b.policy.backoff(attempt, retryAfter), nil
+}
+
+// Build the retryer used by the S3 client from the retry policy
+func (p *RetryPolicy) s3Retryer() aws.Retryer {
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		o.MaxAttempts = p.maxAttempts()
+		if p.MaxBackoff > 0 {
+			o.MaxBackoff = p.MaxBackoff
+		}
+		o.Backoff = s3Backoff{policy: p}
+	})
+}
@@ -0,0 +1,114 @@
+package qarnot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+func TestRetryPolicyRetriesTransientErrors(t *testing.T) {
+	var settingsCalls, tasksCalls atomic.Int32
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/settings" {
+				if settingsCalls.Add(1) < 3 {
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(503)
+					return
+				}
+				fmt.Fprint(w, "{\"storage\": \"https://storage.qarnot.com\"}")
+			} else if r.URL.Path == "/v1/tasks" && r.Method == "POST" {
+				tasksCalls.Add(1)
+				w.WriteHeader(502)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 3,
+			BaseBackoff: time.Millisecond,
+			MaxBackoff:  10 * time.Millisecond,
+		},
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	settings, err := client.GetSettings()
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := Settings{Storage: "https://storage.qarnot.com"}
+	if settings != expectedData || settingsCalls.Load() != 3 {
+		t.Error("different values.")
+		t.Errorf("expected : %v after 3 calls", expectedData)
+		t.Errorf("found    : %v after %v calls", settings, settingsCalls.Load())
+	}
+
+	// A 502 on a POST may come after the task was created, so it must not be retried
+	_, err = client.CreateTask(&CreateTaskPayload{Name: "test"})
+	if err == nil {
+		t.Error("err should not be equal to nil")
+	}
+	if tasksCalls.Load() != 1 {
+		t.Errorf("POST request should have been sent once, found %v calls", tasksCalls.Load())
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, delay := range expected {
+		if found := policy.backoff(i+1, 0); found != delay {
+			t.Errorf("attempt %v, expected : %v, found : %v", i+1, delay, found)
+		}
+	}
+
+	if found := policy.backoff(1, 10*time.Second); found != time.Second {
+		t.Errorf("Retry-After should be capped to MaxBackoff, found : %v", found)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if found := policy.backoff(2, 0); found < 100*time.Millisecond || found > 200*time.Millisecond {
+			t.Fatalf("jittered backoff should be between 100ms and 200ms, found : %v", found)
+		}
+	}
+
+	// Setting only the number of attempts must not retry in a tight loop
+	policy = RetryPolicy{MaxAttempts: 5}
+	if found := policy.backoff(1, 0); found != DefaultRetryPolicy.BaseBackoff {
+		t.Errorf("backoff should default to %v, found : %v", DefaultRetryPolicy.BaseBackoff, found)
+	}
+}
+
+func TestNoRetryPolicyKeepsS3Retries(t *testing.T) {
+	qarnotConfig := QarnotConfig{ApiKey: "xxx", Email: "test@example.org", StorageUrl: "http://fake.storage.qarnope.com"}
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Fatalf("could not create a new client: %v", err)
+	}
+
+	if attempts := client.s3.Options().Retryer.MaxAttempts(); attempts != retry.DefaultMaxAttempts {
+		t.Error("different values.")
+		t.Errorf("expected : %v", retry.DefaultMaxAttempts)
+		t.Errorf("found    : %v", attempts)
+	}
+}
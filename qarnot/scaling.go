@@ -0,0 +1,110 @@
+package qarnot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redat00/qarnot-sdk-go/internal/helpers"
+)
+
+// Enum for the type of a scaling policy
+type ScalingPolicyType string
+
+const (
+	// Keeps a fixed number of slots while the policy is enabled
+	FixedScalingPolicy ScalingPolicyType = "Fixed"
+	// Adjusts the number of slots to the number of tasks waiting in the queue of the pool
+	ManagedTasksQueueScalingPolicy ScalingPolicyType = "ManagedTasksQueue"
+)
+
+// Enum for the type of a time period
+type TimePeriodType string
+
+const (
+	AlwaysTimePeriod          TimePeriodType = "Always"
+	WeeklyRecurringTimePeriod TimePeriodType = "Weekly"
+)
+
+// Struct representing a period of time during which a scaling policy is enabled
+// `Days`, `StartTimeUtc` and `EndTimeUtc` are only used by weekly recurring periods,
+// with days such as "Monday" and times such as "19:30:00"
+type TimePeriod struct {
+	Type         TimePeriodType `json:"type"`
+	Name         string         `json:"name,omitempty"`
+	Days         []string       `json:"days,omitempty"`
+	StartTimeUtc string         `json:"startTimeUtc,omitempty"`
+	EndTimeUtc   string         `json:"endTimeUtc,omitempty"`
+}
+
+// Struct representing a scaling policy of a pool
+// `SlotsCount` is only used by fixed policies, the other settings are only used by managed policies
+// Zero values are sent as is, a fixed policy with no slots scales the pool down to zero
+type ScalingPolicy struct {
+	Type               ScalingPolicyType `json:"type"`
+	Name               string            `json:"name"`
+	EnabledPeriods     []TimePeriod      `json:"enabledPeriods"`
+	SlotsCount         int               `json:"slotsCount"`
+	MinTotalSlots      int               `json:"minTotalSlots"`
+	MaxTotalSlots      int               `json:"maxTotalSlots"`
+	MinIdleSlots       int               `json:"minIdleSlots"`
+	MinIdleTimeSeconds int               `json:"minIdleTimeSeconds"`
+	ScalingFactor      float64           `json:"scalingFactor"`
+}
+
+// The API expects an empty list of enabled periods rather than null
+func (p ScalingPolicy) MarshalJSON() ([]byte, error) {
+	type scalingPolicy ScalingPolicy
+	if p.EnabledPeriods == nil {
+		p.EnabledPeriods = []TimePeriod{}
+	}
+	return json.Marshal(scalingPolicy(p))
+}
+
+// Struct representing the scaling of a pool
+// The first policy having an enabled period matching the current time is the one applied
+type Scaling struct {
+	Policies []ScalingPolicy `json:"policies"`
+}
+
+// Will replace the scaling policies of a pool
+func (c *Client) UpdatePoolScaling(uuid string, scaling Scaling) error {
+	return c.UpdatePoolScalingWithContext(context.Background(), uuid, scaling)
+}
+
+// Same as `UpdatePoolScaling`, but accepting a context to control cancellation and deadlines
+func (c *Client) UpdatePoolScalingWithContext(ctx context.Context, uuid string, scaling Scaling) error {
+	payloadJson, err := json.Marshal(scaling)
+	if err != nil {
+		return helpers.FormatJsonMarshalError(err)
+	}
+
+	_, _, err = c.sendRequest(ctx, "PUT", payloadJson, nil, fmt.Sprintf("pools/%v/scaling", uuid))
+	if err != nil {
+		return fmt.Errorf("could not update pool scaling due to the following error : %w", err)
+	}
+
+	return nil
+}
+
+// Will ask the API to validate a scaling, without applying it to any pool
+// Returns nil when the scaling is valid, otherwise the returned error wraps an `*APIError`
+// whose `FieldErrors` describe the problems found
+func (c *Client) CheckPoolScaling(scaling Scaling) error {
+	return c.CheckPoolScalingWithContext(context.Background(), scaling)
+}
+
+// Same as `CheckPoolScaling`, but accepting a context to control cancellation and deadlines
+func (c *Client) CheckPoolScalingWithContext(ctx context.Context, scaling Scaling) error {
+	payloadJson, err := json.Marshal(scaling)
+	if err != nil {
+		return helpers.FormatJsonMarshalError(err)
+	}
+
+	_, _, err = c.sendRequest(ctx, "POST", payloadJson, nil, "scaling-sanity-check")
+	if err != nil {
+		return fmt.Errorf("pool scaling is not valid : %w", err)
+	}
+
+	return nil
+}
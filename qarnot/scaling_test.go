@@ -0,0 +1,150 @@
+package qarnot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdatePoolScaling(t *testing.T) {
+	var received string
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/pools/okpool/scaling" && r.Method == "PUT" {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+			} else {
+				w.WriteHeader(404)
+				fmt.Fprint(w, `{"message": "No such pool: test"}`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	scaling := Scaling{
+		Policies: []ScalingPolicy{
+			{
+				Type: FixedScalingPolicy,
+				Name: "night",
+				EnabledPeriods: []TimePeriod{
+					{
+						Type:         WeeklyRecurringTimePeriod,
+						Name:         "weeknights",
+						Days:         []string{"Monday", "Tuesday"},
+						StartTimeUtc: "19:00:00",
+						EndTimeUtc:   "23:59:59",
+					},
+				},
+				SlotsCount: 0,
+			},
+			{
+				Type:               ManagedTasksQueueScalingPolicy,
+				Name:               "default",
+				EnabledPeriods:     []TimePeriod{{Type: AlwaysTimePeriod, Name: "always"}},
+				MinTotalSlots:      1,
+				MaxTotalSlots:      10,
+				MinIdleSlots:       1,
+				MinIdleTimeSeconds: 300,
+				ScalingFactor:      0.5,
+			},
+			{
+				Type: FixedScalingPolicy,
+				Name: "disabled",
+			},
+		},
+	}
+	expected := `{"policies":[` +
+		`{"type":"Fixed","name":"night","enabledPeriods":[{"type":"Weekly","name":"weeknights","days":["Monday","Tuesday"],"startTimeUtc":"19:00:00","endTimeUtc":"23:59:59"}],` +
+		`"slotsCount":0,"minTotalSlots":0,"maxTotalSlots":0,"minIdleSlots":0,"minIdleTimeSeconds":0,"scalingFactor":0},` +
+		`{"type":"ManagedTasksQueue","name":"default","enabledPeriods":[{"type":"Always","name":"always"}],` +
+		`"slotsCount":0,"minTotalSlots":1,"maxTotalSlots":10,"minIdleSlots":1,"minIdleTimeSeconds":300,"scalingFactor":0.5},` +
+		`{"type":"Fixed","name":"disabled","enabledPeriods":[],` +
+		`"slotsCount":0,"minTotalSlots":0,"maxTotalSlots":0,"minIdleSlots":0,"minIdleTimeSeconds":0,"scalingFactor":0}]}`
+
+	if err = client.UpdatePoolScaling("okpool", scaling); err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if received != expected {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expected)
+		t.Errorf("found    : %v", received)
+	}
+
+	expectedErrorString := "could not update pool scaling due to the following error : [HTTP 404] No such pool: test"
+	err = client.UpdatePoolScaling("test", scaling)
+	if err.Error() != expectedErrorString {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err.Error())
+	}
+}
+
+func TestCheckPoolScaling(t *testing.T) {
+	expectedInvalid := `{
+		"errors": {
+		  "Policies[0].MaxTotalSlots": [
+			"MaxTotalSlots must be greater than MinTotalSlots."
+		  ]
+		},
+		"title": "One or more validation errors occurred.",
+		"status": 400
+	}`
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var scaling Scaling
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &scaling); err != nil || r.URL.Path != "/v1/scaling-sanity-check" {
+				w.WriteHeader(404)
+				return
+			}
+
+			if scaling.Policies[0].MaxTotalSlots < scaling.Policies[0].MinTotalSlots {
+				w.WriteHeader(400)
+				fmt.Fprint(w, expectedInvalid)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	validScaling := Scaling{Policies: []ScalingPolicy{{Type: ManagedTasksQueueScalingPolicy, MinTotalSlots: 1, MaxTotalSlots: 4}}}
+	if err = client.CheckPoolScaling(validScaling); err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	invalidScaling := Scaling{Policies: []ScalingPolicy{{Type: ManagedTasksQueueScalingPolicy, MinTotalSlots: 4, MaxTotalSlots: 1}}}
+	err = client.CheckPoolScaling(invalidScaling)
+	if !IsValidationError(err) {
+		t.Fatalf("err should be a validation error, found : %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package qarnot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -12,9 +13,14 @@ type Settings struct {
 }
 
 func (c *Client) GetSettings() (Settings, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, "settings")
+	return c.GetSettingsWithContext(context.Background())
+}
+
+// Same as `GetSettings`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetSettingsWithContext(ctx context.Context) (Settings, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, "settings")
 	if err != nil {
-		return Settings{}, fmt.Errorf("could not get settings due to the following error : %v", err)
+		return Settings{}, fmt.Errorf("could not get settings due to the following error : %w", err)
 	}
 
 	var settings Settings
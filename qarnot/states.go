@@ -0,0 +1,105 @@
+package qarnot
+
+import "slices"
+
+// Enum for the state of a task, also used for the state of its instances
+// Unknown states sent by the API are kept as is
+type TaskState string
+
+const (
+	TaskUnSubmitted         TaskState = "UnSubmitted"
+	TaskSubmitted           TaskState = "Submitted"
+	TaskPartiallyDispatched TaskState = "PartiallyDispatched"
+	TaskFullyDispatched     TaskState = "FullyDispatched"
+	TaskPartiallyExecuting  TaskState = "PartiallyExecuting"
+	TaskFullyExecuting      TaskState = "FullyExecuting"
+	TaskDownloadingResults  TaskState = "DownloadingResults"
+	TaskUploadingResults    TaskState = "UploadingResults"
+	TaskPendingCancel       TaskState = "PendingCancel"
+	TaskPendingDelete       TaskState = "PendingDelete"
+	TaskSuccess             TaskState = "Success"
+	TaskFailure             TaskState = "Failure"
+	TaskCancelled           TaskState = "Cancelled"
+)
+
+// States a task can go to from a given state
+// Instances can be retried, so an executing task can go back to being dispatched
+// Any task can be deleted, and any task which did not end yet can be cancelled
+var taskTransitions = map[TaskState][]TaskState{
+	TaskUnSubmitted:         {TaskSubmitted, TaskPendingCancel, TaskPendingDelete},
+	TaskSubmitted:           {TaskPartiallyDispatched, TaskFullyDispatched, TaskPartiallyExecuting, TaskFullyExecuting, TaskSuccess, TaskFailure, TaskCancelled, TaskPendingCancel, TaskPendingDelete},
+	TaskPartiallyDispatched: {TaskFullyDispatched, TaskPartiallyExecuting, TaskFullyExecuting, TaskSuccess, TaskFailure, TaskCancelled, TaskPendingCancel, TaskPendingDelete},
+	TaskFullyDispatched:     {TaskPartiallyDispatched, TaskPartiallyExecuting, TaskFullyExecuting, TaskSuccess, TaskFailure, TaskCancelled, TaskPendingCancel, TaskPendingDelete},
+	TaskPartiallyExecuting:  {TaskPartiallyDispatched, TaskFullyDispatched, TaskFullyExecuting, TaskDownloadingResults, TaskUploadingResults, TaskSuccess, TaskFailure, TaskCancelled, TaskPendingCancel, TaskPendingDelete},
+	TaskFullyExecuting:      {TaskPartiallyDispatched, TaskFullyDispatched, TaskPartiallyExecuting, TaskDownloadingResults, TaskUploadingResults, TaskSuccess, TaskFailure, TaskCancelled, TaskPendingCancel, TaskPendingDelete},
+	TaskDownloadingResults:  {TaskUploadingResults, TaskSuccess, TaskFailure, TaskCancelled, TaskPendingCancel, TaskPendingDelete},
+	TaskUploadingResults:    {TaskSuccess, TaskFailure, TaskCancelled, TaskPendingCancel, TaskPendingDelete},
+	TaskPendingCancel:       {TaskCancelled, TaskPendingDelete},
+	TaskPendingDelete:       {},
+	TaskSuccess:             {TaskPendingDelete},
+	TaskFailure:             {TaskPendingDelete},
+	TaskCancelled:           {TaskPendingDelete},
+}
+
+// Will return true if the state is one of the documented states
+func (s TaskState) IsValid() bool {
+	_, ok := taskTransitions[s]
+	return ok
+}
+
+// Will return true if the task ended, its only remaining transition being its deletion
+func (s TaskState) IsTerminal() bool {
+	return s == TaskSuccess || s == TaskFailure || s == TaskCancelled
+}
+
+// Will return true if at least one instance of the task is being executed
+func (s TaskState) IsRunning() bool {
+	return s == TaskPartiallyExecuting || s == TaskFullyExecuting
+}
+
+// Will return true if a task can go from this state to the next one
+// Always false for unknown states
+func (s TaskState) CanTransitionTo(next TaskState) bool {
+	return slices.Contains(taskTransitions[s], next)
+}
+
+// Enum for the state of a job
+// Unknown states sent by the API are kept as is
+type JobState string
+
+const (
+	JobActive      JobState = "Active"
+	JobTerminating JobState = "Terminating"
+	JobCompleted   JobState = "Completed"
+	JobDeleting    JobState = "Deleting"
+)
+
+// States a job can go to from a given state
+var jobTransitions = map[JobState][]JobState{
+	JobActive:      {JobTerminating, JobCompleted, JobDeleting},
+	JobTerminating: {JobCompleted, JobDeleting},
+	JobCompleted:   {JobDeleting},
+	JobDeleting:    {},
+}
+
+// Will return true if the state is one of the documented states
+func (s JobState) IsValid() bool {
+	_, ok := jobTransitions[s]
+	return ok
+}
+
+// Will return true if the job will not run any task anymore
+func (s JobState) IsTerminal() bool {
+	return s == JobCompleted || s == JobDeleting
+}
+
+// Will return true if the job still accepts and runs tasks
+func (s JobState) IsRunning() bool {
+	return s == JobActive
+}
+
+// Will return true if a job can go from this state to the next one
+// Always false for unknown states
+func (s JobState) CanTransitionTo(next JobState) bool {
+	return slices.Contains(jobTransitions[s], next)
+}
@@ -0,0 +1,105 @@
+package qarnot
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTaskState(t *testing.T) {
+	for _, state := range []TaskState{TaskSuccess, TaskFailure, TaskCancelled} {
+		if !state.IsTerminal() || state.IsRunning() {
+			t.Errorf("%v should be terminal and not running", state)
+		}
+		if state.CanTransitionTo(TaskSubmitted) {
+			t.Errorf("%v should not transition to any state", state)
+		}
+	}
+
+	for _, state := range []TaskState{TaskPartiallyExecuting, TaskFullyExecuting} {
+		if state.IsTerminal() || !state.IsRunning() {
+			t.Errorf("%v should be running and not terminal", state)
+		}
+	}
+
+	if !TaskSubmitted.CanTransitionTo(TaskFullyDispatched) || !TaskFullyExecuting.CanTransitionTo(TaskUploadingResults) {
+		t.Error("forward transitions should be allowed")
+	}
+	if TaskUploadingResults.CanTransitionTo(TaskSubmitted) {
+		t.Error("UploadingResults should not go back to Submitted")
+	}
+	if TaskState("Unknown").IsValid() || TaskState("Unknown").CanTransitionTo(TaskSuccess) {
+		t.Error("unknown states should not be valid")
+	}
+
+	// Cancelling and deleting go through pending states
+	for _, state := range []TaskState{TaskUnSubmitted, TaskSubmitted, TaskFullyDispatched, TaskFullyExecuting, TaskDownloadingResults, TaskUploadingResults} {
+		if !state.CanTransitionTo(TaskPendingCancel) || !state.CanTransitionTo(TaskPendingDelete) {
+			t.Errorf("%v should be able to go to PendingCancel and PendingDelete", state)
+		}
+	}
+	if !TaskPendingCancel.CanTransitionTo(TaskCancelled) || TaskPendingCancel.IsTerminal() || TaskPendingDelete.CanTransitionTo(TaskCancelled) {
+		t.Error("PendingCancel should go to Cancelled")
+	}
+	if TaskSuccess.CanTransitionTo(TaskPendingCancel) || !TaskSuccess.CanTransitionTo(TaskPendingDelete) {
+		t.Error("ended tasks should only be deleted")
+	}
+}
+
+func TestJobState(t *testing.T) {
+	if !JobActive.IsRunning() || JobActive.IsTerminal() {
+		t.Error("Active should be running and not terminal")
+	}
+	if !JobCompleted.IsTerminal() || JobCompleted.IsRunning() {
+		t.Error("Completed should be terminal and not running")
+	}
+	if !JobActive.CanTransitionTo(JobTerminating) || JobCompleted.CanTransitionTo(JobActive) {
+		t.Error("unexpected transitions")
+	}
+	if !JobDeleting.IsValid() || JobState("Unknown").IsValid() {
+		t.Error("unexpected validity")
+	}
+}
+
+func TestStatesJson(t *testing.T) {
+	data := `{"state":"FullyExecuting","previousState":"SomethingNew","completedInstances":[{"state":"Failure"}]}`
+
+	var task Task
+	err := json.Unmarshal([]byte(data), &task)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	if task.State != TaskFullyExecuting || task.PreviousState != "SomethingNew" || task.CompletedInstances[0].State != TaskFailure {
+		t.Error("different values.")
+		t.Errorf("found    : %v %v %v", task.State, task.PreviousState, task.CompletedInstances[0].State)
+	}
+
+	for _, state := range []TaskState{TaskUnSubmitted, TaskPendingCancel, TaskPendingDelete} {
+		var pending Task
+		err = json.Unmarshal([]byte(`{"state":"`+string(state)+`"}`), &pending)
+		if err != nil || pending.State != state || !pending.State.IsValid() {
+			t.Errorf("%v should be decoded as a valid state, found %v (%v)", state, pending.State, err)
+		}
+	}
+
+	var job Job
+	err = json.Unmarshal([]byte(`{"state":"Terminating"}`), &job)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	encoded, err := json.Marshal(struct {
+		Task TaskState
+		Job  JobState
+	}{task.State, job.State})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expected := `{"Task":"FullyExecuting","Job":"Terminating"}`
+	if string(encoded) != expected {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expected)
+		t.Errorf("found    : %v", string(encoded))
+	}
+}
@@ -0,0 +1,381 @@
+package qarnot
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Enum for the action of a sync operation
+type SyncAction string
+
+const (
+	SyncUpload   SyncAction = "upload"
+	SyncDownload SyncAction = "download"
+	SyncDelete   SyncAction = "delete"
+	// Local files which cannot be synced, such as sockets or symlinks to directories, are only reported
+	SyncSkip SyncAction = "skip"
+)
+
+// Struct representing an operation done, or planned when running dry, by a sync
+type SyncOperation struct {
+	Action SyncAction
+	// Key of the object in the bucket
+	Key string
+	// Path of the file in the local directory
+	LocalPath string
+	// Size of the transferred file, 0 for deletions
+	Size int64
+}
+
+func (o SyncOperation) String() string {
+	if o.Action == SyncSkip {
+		return fmt.Sprintf("skip %v", o.LocalPath)
+	} else if o.Action == SyncDelete && o.LocalPath != "" {
+		return fmt.Sprintf("delete %v", o.LocalPath)
+	} else if o.Action == SyncDelete {
+		return fmt.Sprintf("delete %v", o.Key)
+	} else if o.Action == SyncDownload {
+		return fmt.Sprintf("download %v -> %v (%v bytes)", o.Key, o.LocalPath, o.Size)
+	}
+	return fmt.Sprintf("upload %v -> %v (%v bytes)", o.LocalPath, o.Key, o.Size)
+}
+
+// Struct representing the options of `SyncDirToBucket` and `SyncBucketToDir`
+type SyncOptions struct {
+	// Prefix of the keys in the bucket matching the local directory, the root of the bucket when empty
+	Prefix string
+	// Also delete the files or objects of the destination which are not in the source
+	Delete bool
+	// Ignore the files and objects matching any of these globs, on both sides
+	// Globs are matched against the path relative to the directory, as well as against the base name
+	// Directories matching a glob are ignored with everything they contain, so ".git/*" also ignores ".git/objects/ab/cd"
+	Exclude []string
+	// Only return the operations which would be done, without doing them
+	DryRun bool
+	// Called after each operation, or for each planned operation when running dry
+	OnOperation func(operation SyncOperation)
+	// Number of operations done at the same time, 4 by default
+	Concurrency int
+}
+
+// A file of the local directory
+type localFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Will list the regular files of a local directory, by path relative to the directory
+// Symlinks to regular files are followed, other entries which are neither regular files nor
+// directories are returned as skipped
+func listLocalFiles(localDir string, exclude []string) (map[string]localFile, []string, error) {
+	files := map[string]localFile{}
+	skipped := []string{}
+	err := filepath.WalkDir(localDir, func(localPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if localPath == localDir {
+			return nil
+		}
+
+		relative, err := filepath.Rel(localDir, localPath)
+		if err != nil {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+
+		if excluded, err := matchesAnyGlob(exclude, relative); err != nil {
+			return err
+		} else if excluded && entry.IsDir() {
+			return filepath.SkipDir
+		} else if excluded || entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			info, err = os.Stat(localPath)
+			if errors.Is(err, fs.ErrNotExist) {
+				skipped = append(skipped, localPath)
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+		if !info.Mode().IsRegular() {
+			skipped = append(skipped, localPath)
+			return nil
+		}
+
+		files[relative] = localFile{path: localPath, size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return files, skipped, err
+}
+
+// Will return true if the key, or one of its parent directories, matches one of the globs
+// Objects are excluded the same way as the files of a local directory whose walk skips excluded directories
+func isExcludedKey(exclude []string, relative string) (bool, error) {
+	for name := relative; name != "." && name != "/"; name = path.Dir(name) {
+		if excluded, err := matchesAnyGlob(exclude, name); err != nil || excluded {
+			return excluded, err
+		}
+	}
+	return false, nil
+}
+
+// Will list the objects of a bucket under a prefix, by key relative to the prefix
+func (c *Client) listSyncObjects(ctx context.Context, bucketName string, prefix string, exclude []string) (map[string]BucketObject, error) {
+	listing, err := c.ListBucketObjectsWithContext(ctx, bucketName, ListObjectsOptions{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := map[string]BucketObject{}
+	for _, object := range listing.Objects {
+		relative := strings.TrimPrefix(object.Name, prefix)
+		// Keys ending with a slash only represent directories
+		if relative == "" || strings.HasSuffix(relative, "/") {
+			continue
+		}
+		if excluded, err := isExcludedKey(exclude, relative); err != nil {
+			return nil, err
+		} else if !excluded {
+			byKey[relative] = object
+		}
+	}
+	return byKey, nil
+}
+
+// Will return true if a local file and an object have the same content
+// Their sizes are compared first, then the MD5 of the file when the ETag is a plain MD5,
+// and otherwise their modification times, the destination being up to date when it is the most recent
+func sameContent(file localFile, object BucketObject, sourceIsLocal bool) (bool, error) {
+	if file.size != object.Size {
+		return false, nil
+	}
+
+	etag := strings.Trim(object.ETag, `"`)
+	if len(etag) == 32 && !strings.Contains(etag, "-") {
+		f, err := os.Open(file.path)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+
+		hash := md5.New()
+		if _, err := io.Copy(hash, f); err != nil {
+			return false, err
+		}
+		return strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), etag), nil
+	}
+
+	if sourceIsLocal {
+		return !file.modTime.After(object.LastModified), nil
+	}
+	return !object.LastModified.After(file.modTime), nil
+}
+
+// Normalize a prefix so that it matches a directory
+func syncPrefix(prefix string) string {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		return prefix + "/"
+	}
+	return prefix
+}
+
+// Will upload the files of a local directory which are missing or different in a bucket
+// Symlinks to regular files are followed, while the other local entries which are not regular files,
+// such as symlinks to directories, are not synced but reported as `SyncSkip` operations
+// Returns the operations done, or the ones which would be done when running dry
+func (c *Client) SyncDirToBucket(localDir string, bucketName string, options SyncOptions) ([]SyncOperation, error) {
+	return c.SyncDirToBucketWithContext(context.Background(), localDir, bucketName, options)
+}
+
+// Same as `SyncDirToBucket`, but accepting a context to control cancellation and deadlines
+func (c *Client) SyncDirToBucketWithContext(ctx context.Context, localDir string, bucketName string, options SyncOptions) ([]SyncOperation, error) {
+	prefix := syncPrefix(options.Prefix)
+
+	files, skipped, err := listLocalFiles(localDir, options.Exclude)
+	if err != nil {
+		return []SyncOperation{}, fmt.Errorf("could not sync directory to bucket due to the following error : %w", err)
+	}
+
+	objects, err := c.listSyncObjects(ctx, bucketName, prefix, options.Exclude)
+	if err != nil {
+		return []SyncOperation{}, fmt.Errorf("could not sync directory to bucket due to the following error : %w", err)
+	}
+
+	operations := []SyncOperation{}
+	for _, localPath := range skipped {
+		operations = append(operations, SyncOperation{Action: SyncSkip, LocalPath: localPath})
+	}
+	for relative, file := range files {
+		object, exists := objects[relative]
+		if exists {
+			same, err := sameContent(file, object, true)
+			if err != nil {
+				return []SyncOperation{}, fmt.Errorf("could not sync directory to bucket due to the following error : %w", err)
+			}
+			if same {
+				continue
+			}
+		}
+		operations = append(operations, SyncOperation{Action: SyncUpload, Key: prefix + relative, LocalPath: file.path, Size: file.size})
+	}
+	if options.Delete {
+		for relative, object := range objects {
+			if _, exists := files[relative]; !exists {
+				operations = append(operations, SyncOperation{Action: SyncDelete, Key: object.Name})
+			}
+		}
+	}
+
+	done, err := c.runSync(ctx, bucketName, operations, options)
+	if err != nil {
+		return done, fmt.Errorf("could not sync directory to bucket due to the following error : %w", err)
+	}
+
+	return done, nil
+}
+
+// Will download the objects of a bucket which are missing or different in a local directory
+// Local entries are handled as in `SyncDirToBucket`, those which are not regular files being reported as `SyncSkip`
+// operations and never deleted
+// Returns the operations done, or the ones which would be done when running dry
+func (c *Client) SyncBucketToDir(bucketName string, localDir string, options SyncOptions) ([]SyncOperation, error) {
+	return c.SyncBucketToDirWithContext(context.Background(), bucketName, localDir, options)
+}
+
+// Same as `SyncBucketToDir`, but accepting a context to control cancellation and deadlines
+func (c *Client) SyncBucketToDirWithContext(ctx context.Context, bucketName string, localDir string, options SyncOptions) ([]SyncOperation, error) {
+	prefix := syncPrefix(options.Prefix)
+
+	err := os.MkdirAll(localDir, 0o755)
+	if err != nil {
+		return []SyncOperation{}, fmt.Errorf("could not sync bucket to directory due to the following error : %w", err)
+	}
+
+	root, err := filepath.Abs(localDir)
+	if err != nil {
+		return []SyncOperation{}, fmt.Errorf("could not sync bucket to directory due to the following error : %w", err)
+	}
+
+	files, skipped, err := listLocalFiles(root, options.Exclude)
+	if err != nil {
+		return []SyncOperation{}, fmt.Errorf("could not sync bucket to directory due to the following error : %w", err)
+	}
+
+	objects, err := c.listSyncObjects(ctx, bucketName, prefix, options.Exclude)
+	if err != nil {
+		return []SyncOperation{}, fmt.Errorf("could not sync bucket to directory due to the following error : %w", err)
+	}
+
+	operations := []SyncOperation{}
+	for _, localPath := range skipped {
+		operations = append(operations, SyncOperation{Action: SyncSkip, LocalPath: localPath})
+	}
+	for relative, object := range objects {
+		file, exists := files[relative]
+		if exists {
+			same, err := sameContent(file, object, false)
+			if err != nil {
+				return []SyncOperation{}, fmt.Errorf("could not sync bucket to directory due to the following error : %w", err)
+			}
+			if same {
+				continue
+			}
+		}
+
+		localPath := filepath.Join(root, filepath.FromSlash(relative))
+		if !strings.HasPrefix(localPath, root+string(filepath.Separator)) {
+			return []SyncOperation{}, fmt.Errorf("could not sync bucket to directory : %v would be written outside of %v", object.Name, localDir)
+		}
+		operations = append(operations, SyncOperation{Action: SyncDownload, Key: object.Name, LocalPath: localPath, Size: object.Size})
+	}
+	if options.Delete {
+		for relative, file := range files {
+			if _, exists := objects[relative]; !exists {
+				operations = append(operations, SyncOperation{Action: SyncDelete, LocalPath: file.path})
+			}
+		}
+	}
+
+	done, err := c.runSync(ctx, bucketName, operations, options)
+	if err != nil {
+		return done, fmt.Errorf("could not sync bucket to directory due to the following error : %w", err)
+	}
+
+	return done, nil
+}
+
+// Will run the operations of a sync, unless running dry
+func (c *Client) runSync(ctx context.Context, bucketName string, operations []SyncOperation, options SyncOptions) ([]SyncOperation, error) {
+	slices.SortFunc(operations, func(a, b SyncOperation) int {
+		return strings.Compare(a.Key+a.LocalPath, b.Key+b.LocalPath)
+	})
+
+	if options.DryRun {
+		if options.OnOperation != nil {
+			for _, operation := range operations {
+				options.OnOperation(operation)
+			}
+		}
+		return operations, nil
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var mu sync.Mutex
+	done := []SyncOperation{}
+	manager := c.NewTransferManager(TransferOptions{})
+	err := forEachParallel(ctx, concurrency, operations, func(ctx context.Context, operation SyncOperation) error {
+		var err error
+		switch {
+		case operation.Action == SyncSkip:
+		case operation.Action == SyncUpload:
+			err = manager.UploadWithContext(ctx, bucketName, operation.Key, operation.LocalPath)
+		case operation.Action == SyncDownload:
+			err = manager.DownloadWithContext(ctx, bucketName, operation.Key, operation.LocalPath)
+		case operation.LocalPath != "":
+			err = os.Remove(operation.LocalPath)
+		default:
+			err = c.DeleteObjectWithContext(ctx, ObjectToDelete{Bucket: bucketName, Key: operation.Key})
+		}
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		done = append(done, operation)
+		if options.OnOperation != nil {
+			options.OnOperation(operation)
+		}
+		return nil
+	})
+
+	slices.SortFunc(done, func(a, b SyncOperation) int {
+		return strings.Compare(a.Key+a.LocalPath, b.Key+b.LocalPath)
+	})
+	return done, err
+}
@@ -0,0 +1,148 @@
+package qarnot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncDirToBucket(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "inputs/unchanged.txt", "unchanged")
+	storage.put("bucket", "inputs/changed.txt", "old")
+	storage.put("bucket", "inputs/extraneous.txt", "extraneous")
+	storage.put("bucket", "inputs/cache/kept.tmp", "excluded")
+	storage.put("bucket", "inputs/.git/objects/ab/cd", "excluded")
+	storage.put("bucket", "outside.txt", "outside")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	localDir := t.TempDir()
+	os.MkdirAll(filepath.Join(localDir, "scenes"), 0o755)
+	os.WriteFile(filepath.Join(localDir, "unchanged.txt"), []byte("unchanged"), 0o644)
+	os.WriteFile(filepath.Join(localDir, "changed.txt"), []byte("new"), 0o644)
+	os.WriteFile(filepath.Join(localDir, "scenes", "scene.blend"), []byte("scene"), 0o644)
+	os.WriteFile(filepath.Join(localDir, "ignored.tmp"), []byte("ignored"), 0o644)
+	os.MkdirAll(filepath.Join(localDir, ".git", "objects", "ef"), 0o755)
+	os.WriteFile(filepath.Join(localDir, ".git", "HEAD"), []byte("ignored"), 0o644)
+	os.WriteFile(filepath.Join(localDir, ".git", "objects", "ef", "gh"), []byte("ignored"), 0o644)
+
+	options := SyncOptions{Prefix: "inputs", Delete: true, Exclude: []string{"*.tmp", ".git/*"}, DryRun: true}
+	operations, err := client.SyncDirToBucket(localDir, "bucket", options)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := []SyncOperation{
+		{Action: SyncUpload, Key: "inputs/changed.txt", LocalPath: filepath.Join(localDir, "changed.txt"), Size: 3},
+		{Action: SyncDelete, Key: "inputs/extraneous.txt"},
+		{Action: SyncUpload, Key: "inputs/scenes/scene.blend", LocalPath: filepath.Join(localDir, "scenes", "scene.blend"), Size: 5},
+	}
+	if fmt.Sprint(operations) != fmt.Sprint(expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedData)
+		t.Errorf("found    : %v", operations)
+	}
+	if object, _ := storage.get("bucket", "inputs/changed.txt"); string(object.data) != "old" {
+		t.Error("nothing should be uploaded when running dry")
+	}
+
+	options.DryRun = false
+	operations, err = client.SyncDirToBucket(localDir, "bucket", options)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if fmt.Sprint(operations) != fmt.Sprint(expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedData)
+		t.Errorf("found    : %v", operations)
+	}
+
+	for key, expected := range map[string]string{"inputs/changed.txt": "new", "inputs/scenes/scene.blend": "scene", "inputs/cache/kept.tmp": "excluded", "inputs/.git/objects/ab/cd": "excluded", "outside.txt": "outside"} {
+		if object, _ := storage.get("bucket", key); string(object.data) != expected {
+			t.Errorf("unexpected content for %v : %q", key, object.data)
+		}
+	}
+	if _, exists := storage.get("bucket", "inputs/extraneous.txt"); exists {
+		t.Error("inputs/extraneous.txt should be deleted")
+	}
+
+	// Nothing to do once in sync
+	operations, err = client.SyncDirToBucket(localDir, "bucket", options)
+	if err != nil || len(operations) != 0 {
+		t.Errorf("nothing should be done once in sync, found : %v (%v)", operations, err)
+	}
+}
+
+func TestSyncDirToBucketSymlinks(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	localDir := t.TempDir()
+	outside := t.TempDir()
+	os.WriteFile(filepath.Join(outside, "shared.txt"), []byte("shared"), 0o644)
+	os.Symlink(filepath.Join(outside, "shared.txt"), filepath.Join(localDir, "file-link.txt"))
+	os.Symlink(outside, filepath.Join(localDir, "dir-link"))
+	os.Symlink(filepath.Join(outside, "missing.txt"), filepath.Join(localDir, "dangling-link.txt"))
+
+	var reported []SyncOperation
+	operations, err := client.SyncDirToBucket(localDir, "bucket", SyncOptions{OnOperation: func(operation SyncOperation) {
+		reported = append(reported, operation)
+	}})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	// Symlinks to files are followed, the other ones are reported as skipped
+	expectedData := []SyncOperation{
+		{Action: SyncSkip, LocalPath: filepath.Join(localDir, "dangling-link.txt")},
+		{Action: SyncSkip, LocalPath: filepath.Join(localDir, "dir-link")},
+		{Action: SyncUpload, Key: "file-link.txt", LocalPath: filepath.Join(localDir, "file-link.txt"), Size: 6},
+	}
+	if fmt.Sprint(operations) != fmt.Sprint(expectedData) || len(reported) != 3 {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedData)
+		t.Errorf("found    : %v (%v reported)", operations, len(reported))
+	}
+	if object, _ := storage.get("bucket", "file-link.txt"); string(object.data) != "shared" {
+		t.Errorf("unexpected content for file-link.txt : %q", object.data)
+	}
+}
+
+func TestSyncBucketToDir(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "results/frame-1.png", "frame 1")
+	storage.put("bucket", "results/frame-2.png", "frame 2")
+	storage.put("bucket", "results/logs/out.log", "excluded")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	localDir := t.TempDir()
+	os.WriteFile(filepath.Join(localDir, "frame-1.png"), []byte("frame 1"), 0o644)
+	os.WriteFile(filepath.Join(localDir, "frame-3.png"), []byte("frame 3"), 0o644)
+
+	operations, err := client.SyncBucketToDir("bucket", localDir, SyncOptions{Prefix: "results/", Delete: true, Exclude: []string{"logs/*"}})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := []SyncOperation{
+		{Action: SyncDelete, LocalPath: filepath.Join(localDir, "frame-3.png")},
+		{Action: SyncDownload, Key: "results/frame-2.png", LocalPath: filepath.Join(localDir, "frame-2.png"), Size: 7},
+	}
+	if fmt.Sprint(operations) != fmt.Sprint(expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedData)
+		t.Errorf("found    : %v", operations)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(localDir, "frame-2.png")); err != nil || string(data) != "frame 2" {
+		t.Errorf("unexpected content for frame-2.png : %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(localDir, "frame-3.png")); !os.IsNotExist(err) {
+		t.Error("frame-3.png should be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(localDir, "logs")); !os.IsNotExist(err) {
+		t.Error("excluded objects should not be downloaded")
+	}
+}
@@ -1,6 +1,7 @@
 package qarnot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -180,7 +181,7 @@ type CompletedInstance struct {
 	ExecTimeSec           float32      `json:"execTimeSec"`
 	ExecTimeSecGHz        float32      `json:"execTimeSecGHz"`
 	PeakMemoryMB          int          `json:"peakMemoryMB"`
-	State                 string       `json:"state"`
+	State                 TaskState    `json:"state"`
 	Error                 QErrorPublic `json:"error"`
 	SpecificationKey      string       `json:"specificationKey"`
 	CpuModel              string       `json:"cpuModel"`
@@ -262,6 +263,9 @@ type Task struct {
 	ResourceBuckets                     []string                     `json:"resourceBuckets,omitempty"`
 	AdvancedResourceBuckets             []TaskAdvancedResourceBucket `json:"advancedResourceBuckets,omitempty"`
 	ResultBucket                        string                       `json:"resultBucket,omitempty"`
+	ResultsBucketPrefix                 string                       `json:"resultsBucketPrefix,omitempty"`
+	ResultsWhitelist                    string                       `json:"resultsWhitelist,omitempty"`
+	ResultsBlacklist                    string                       `json:"resultsBlacklist,omitempty"`
 	CompletedInstances                  []CompletedInstance          `json:"completedInstances,omitempty"`
 	Status                              TaskStatus                   `json:"status,omitempty"`
 	SnapshotInterval                    int                          `json:"snapshotInterval,omitempty"`
@@ -291,8 +295,8 @@ type Task struct {
 	RunningCoreCount                    int                          `json:"runningCoreCount,omitempty"`
 	ExecutionTime                       string                       `json:"executionTime,omitempty"`
 	WallTime                            string                       `json:"wallTime,omitempty"`
-	State                               string                       `json:"state,omitempty"`
-	PreviousState                       string                       `json:"previousState,omitempty"`
+	State                               TaskState                    `json:"state,omitempty"`
+	PreviousState                       TaskState                    `json:"previousState,omitempty"`
 	InstanceCount                       int                          `json:"instanceCount,omitempty"`
 	MaxRetriesPerInstance               int                          `json:"maxRetriesPerInstance,omitempty"`
 	StateTransitionTime                 time.Time                    `json:"stateTransitionTime,omitempty"`
@@ -378,8 +382,8 @@ type TaskSummary struct {
 	RunningCoreCount                    int
 	ExecutionTime                       string
 	WallTime                            string
-	State                               string
-	PreviousState                       string
+	State                               TaskState
+	PreviousState                       TaskState
 	InstanceCount                       int
 	MaxRetriesPerInstance               int
 	AdvancedRanges                      string
@@ -394,6 +398,11 @@ type TaskSummary struct {
 // Will list the tasks for the authenticated user
 // Optionally filter the results if any tags are provided
 func (c *Client) ListTasks(tags ...string) ([]Task, error) {
+	return c.ListTasksWithContext(context.Background(), tags...)
+}
+
+// Same as `ListTasks`, but accepting a context to control cancellation and deadlines
+func (c *Client) ListTasksWithContext(ctx context.Context, tags ...string) ([]Task, error) {
 	addQuery := func(req *http.Request) error {
 		query := req.URL.Query()
 		for _, tag := range tags {
@@ -404,6 +413,7 @@ func (c *Client) ListTasks(tags ...string) ([]Task, error) {
 	}
 
 	data, _, err := c.sendRequest(
+		ctx,
 		"GET",
 		[]byte{},
 		make(map[string]string),
@@ -411,7 +421,34 @@ func (c *Client) ListTasks(tags ...string) ([]Task, error) {
 		addQuery,
 	)
 	if err != nil {
-		return []Task{}, fmt.Errorf("could not list tasks due to the following error : %v", err)
+		return []Task{}, fmt.Errorf("could not list tasks due to the following error : %w", err)
+	}
+
+	var tasks []Task
+	err = json.Unmarshal(data, &tasks)
+	if err != nil {
+		return tasks, helpers.FormatJsonUnmarshalError(err)
+	}
+
+	return tasks, nil
+}
+
+// Will search the tasks of the authenticated user, filtering them on the API side
+// See `Filter` for how to build the filter of the search
+func (c *Client) SearchTasks(options SearchOptions) ([]Task, error) {
+	return c.SearchTasksWithContext(context.Background(), options)
+}
+
+// Same as `SearchTasks`, but accepting a context to control cancellation and deadlines
+func (c *Client) SearchTasksWithContext(ctx context.Context, options SearchOptions) ([]Task, error) {
+	payloadJson, err := json.Marshal(options)
+	if err != nil {
+		return []Task{}, helpers.FormatJsonMarshalError(err)
+	}
+
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, "tasks/search")
+	if err != nil {
+		return []Task{}, fmt.Errorf("could not search tasks due to the following error : %w", err)
 	}
 
 	var tasks []Task
@@ -425,9 +462,14 @@ func (c *Client) ListTasks(tags ...string) ([]Task, error) {
 
 // Will get the info for a task
 func (c *Client) GetTaskInfo(uuid string) (Task, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, fmt.Sprintf("tasks/%v", uuid))
+	return c.GetTaskInfoWithContext(context.Background(), uuid)
+}
+
+// Same as `GetTaskInfo`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetTaskInfoWithContext(ctx context.Context, uuid string) (Task, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("tasks/%v", uuid))
 	if err != nil {
-		return Task{}, fmt.Errorf("could not get task info due to the following error : %v", err)
+		return Task{}, fmt.Errorf("could not get task info due to the following error : %w", err)
 	}
 
 	var taskInfo Task
@@ -442,6 +484,11 @@ func (c *Client) GetTaskInfo(uuid string) (Task, error) {
 // Will create a task, based on a `CreateTaskPayload`
 // Returns a `UUIDResponse` struct, containing a UUID for the newly created task
 func (c *Client) CreateTask(payload *CreateTaskPayload) (UUIDResponse, error) {
+	return c.CreateTaskWithContext(context.Background(), payload)
+}
+
+// Same as `CreateTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) CreateTaskWithContext(ctx context.Context, payload *CreateTaskPayload) (UUIDResponse, error) {
 	var response UUIDResponse
 
 	payloadJson, err := json.Marshal(payload)
@@ -449,9 +496,9 @@ func (c *Client) CreateTask(payload *CreateTaskPayload) (UUIDResponse, error) {
 		return UUIDResponse{}, helpers.FormatJsonMarshalError(err)
 	}
 
-	data, _, err := c.sendRequest("POST", payloadJson, nil, "tasks")
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, "tasks")
 	if err != nil {
-		return UUIDResponse{}, fmt.Errorf("could not create task due to the following error : %v", err)
+		return UUIDResponse{}, fmt.Errorf("could not create task due to the following error : %w", err)
 	}
 
 	err = json.Unmarshal(data, &response)
@@ -464,9 +511,14 @@ func (c *Client) CreateTask(payload *CreateTaskPayload) (UUIDResponse, error) {
 
 // Will list task summaries for the authenticated user
 func (c *Client) ListTaskSummaries() ([]TaskSummary, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, "tasks/summaries")
+	return c.ListTaskSummariesWithContext(context.Background())
+}
+
+// Same as `ListTaskSummaries`, but accepting a context to control cancellation and deadlines
+func (c *Client) ListTaskSummariesWithContext(ctx context.Context) ([]TaskSummary, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, "tasks/summaries")
 	if err != nil {
-		return []TaskSummary{}, fmt.Errorf("could not list task summaries due to the following error : %v", err)
+		return []TaskSummary{}, fmt.Errorf("could not list task summaries due to the following error : %w", err)
 	}
 
 	var summaries []TaskSummary
@@ -480,27 +532,42 @@ func (c *Client) ListTaskSummaries() ([]TaskSummary, error) {
 
 // Will delete a task
 func (c *Client) DeleteTask(uuid string) error {
-	_, _, err := c.sendRequest("DELETE", []byte{}, nil, fmt.Sprintf("tasks/%v", uuid))
+	return c.DeleteTaskWithContext(context.Background(), uuid)
+}
+
+// Same as `DeleteTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) DeleteTaskWithContext(ctx context.Context, uuid string) error {
+	_, _, err := c.sendRequest(ctx, "DELETE", []byte{}, nil, fmt.Sprintf("tasks/%v", uuid))
 	if err != nil {
-		return fmt.Errorf("could not delete task due to the following error : %v", err)
+		return fmt.Errorf("could not delete task due to the following error : %w", err)
 	}
 	return nil
 }
 
 // Will abort a task
 func (c *Client) AbortTask(uuid string) error {
-	_, _, err := c.sendRequest("POST", []byte{}, nil, fmt.Sprintf("tasks/%v/abort", uuid))
+	return c.AbortTaskWithContext(context.Background(), uuid)
+}
+
+// Same as `AbortTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) AbortTaskWithContext(ctx context.Context, uuid string) error {
+	_, _, err := c.sendRequest(ctx, "POST", []byte{}, nil, fmt.Sprintf("tasks/%v/abort", uuid))
 	if err != nil {
-		return fmt.Errorf("could not abort task due to the following error : %v", err)
+		return fmt.Errorf("could not abort task due to the following error : %w", err)
 	}
 	return nil
 }
 
 // Will get the stdout for a task
 func (c *Client) GetTaskStdout(uuid string) (string, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, fmt.Sprintf("tasks/%v/stdout", uuid))
+	return c.GetTaskStdoutWithContext(context.Background(), uuid)
+}
+
+// Same as `GetTaskStdout`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetTaskStdoutWithContext(ctx context.Context, uuid string) (string, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("tasks/%v/stdout", uuid))
 	if err != nil {
-		return "", fmt.Errorf("could not get task stdout due to the following error : %v", err)
+		return "", fmt.Errorf("could not get task stdout due to the following error : %w", err)
 	}
 
 	var stdout string
@@ -514,9 +581,14 @@ func (c *Client) GetTaskStdout(uuid string) (string, error) {
 
 // Will get the previous stdout for a task
 func (c *Client) GetLastTaskStdout(uuid string) (string, error) {
-	data, _, err := c.sendRequest("POST", []byte{}, nil, fmt.Sprintf("tasks/%v/stdout", uuid))
+	return c.GetLastTaskStdoutWithContext(context.Background(), uuid)
+}
+
+// Same as `GetLastTaskStdout`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetLastTaskStdoutWithContext(ctx context.Context, uuid string) (string, error) {
+	data, _, err := c.sendRequest(ctx, "POST", []byte{}, nil, fmt.Sprintf("tasks/%v/stdout", uuid))
 	if err != nil {
-		return "", fmt.Errorf("could not get last task stdout due to the following error : %v", err)
+		return "", fmt.Errorf("could not get last task stdout due to the following error : %w", err)
 	}
 
 	var stdout string
@@ -530,9 +602,14 @@ func (c *Client) GetLastTaskStdout(uuid string) (string, error) {
 
 // Will get the stdout for a task on a specific instance
 func (c *Client) GetTaskInstanceStdout(uuid string, instanceId int) (string, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, fmt.Sprintf("tasks/%v/stdout/%v", uuid, instanceId))
+	return c.GetTaskInstanceStdoutWithContext(context.Background(), uuid, instanceId)
+}
+
+// Same as `GetTaskInstanceStdout`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetTaskInstanceStdoutWithContext(ctx context.Context, uuid string, instanceId int) (string, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("tasks/%v/stdout/%v", uuid, instanceId))
 	if err != nil {
-		return "", fmt.Errorf("could not get task instance stdout due to the following error : %v", err)
+		return "", fmt.Errorf("could not get task instance stdout due to the following error : %w", err)
 	}
 
 	var stdout string
@@ -546,9 +623,14 @@ func (c *Client) GetTaskInstanceStdout(uuid string, instanceId int) (string, err
 
 // Will get the previous stdout for a task on a specific instance
 func (c *Client) GetLastTaskInstanceStdout(uuid string, instanceId int) (string, error) {
-	data, _, err := c.sendRequest("POST", []byte{}, nil, fmt.Sprintf("tasks/%v/stdout/%v", uuid, instanceId))
+	return c.GetLastTaskInstanceStdoutWithContext(context.Background(), uuid, instanceId)
+}
+
+// Same as `GetLastTaskInstanceStdout`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetLastTaskInstanceStdoutWithContext(ctx context.Context, uuid string, instanceId int) (string, error) {
+	data, _, err := c.sendRequest(ctx, "POST", []byte{}, nil, fmt.Sprintf("tasks/%v/stdout/%v", uuid, instanceId))
 	if err != nil {
-		return "", fmt.Errorf("could not get last task instance stdout due to the following error : %v", err)
+		return "", fmt.Errorf("could not get last task instance stdout due to the following error : %w", err)
 	}
 
 	var stdout string
@@ -562,9 +644,14 @@ func (c *Client) GetLastTaskInstanceStdout(uuid string, instanceId int) (string,
 
 // Will get the stderr for a task
 func (c *Client) GetTaskStderr(uuid string) (string, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, fmt.Sprintf("tasks/%v/stderr", uuid))
+	return c.GetTaskStderrWithContext(context.Background(), uuid)
+}
+
+// Same as `GetTaskStderr`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetTaskStderrWithContext(ctx context.Context, uuid string) (string, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("tasks/%v/stderr", uuid))
 	if err != nil {
-		return "", fmt.Errorf("could not get task stderr due to the following error : %v", err)
+		return "", fmt.Errorf("could not get task stderr due to the following error : %w", err)
 	}
 
 	var stderr string
@@ -578,9 +665,14 @@ func (c *Client) GetTaskStderr(uuid string) (string, error) {
 
 // Will get the previous stderr for a task
 func (c *Client) GetLastTaskStderr(uuid string) (string, error) {
-	data, _, err := c.sendRequest("POST", []byte{}, nil, fmt.Sprintf("tasks/%v/stderr", uuid))
+	return c.GetLastTaskStderrWithContext(context.Background(), uuid)
+}
+
+// Same as `GetLastTaskStderr`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetLastTaskStderrWithContext(ctx context.Context, uuid string) (string, error) {
+	data, _, err := c.sendRequest(ctx, "POST", []byte{}, nil, fmt.Sprintf("tasks/%v/stderr", uuid))
 	if err != nil {
-		return "", fmt.Errorf("could not get last task stderr due to the following error : %v", err)
+		return "", fmt.Errorf("could not get last task stderr due to the following error : %w", err)
 	}
 
 	var stderr string
@@ -594,9 +686,14 @@ func (c *Client) GetLastTaskStderr(uuid string) (string, error) {
 
 // Will get the stderr for a task on a specific instance
 func (c *Client) GetTaskInstanceStderr(uuid string, instanceId int) (string, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, nil, fmt.Sprintf("tasks/%v/stderr/%v", uuid, instanceId))
+	return c.GetTaskInstanceStderrWithContext(context.Background(), uuid, instanceId)
+}
+
+// Same as `GetTaskInstanceStderr`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetTaskInstanceStderrWithContext(ctx context.Context, uuid string, instanceId int) (string, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, nil, fmt.Sprintf("tasks/%v/stderr/%v", uuid, instanceId))
 	if err != nil {
-		return "", fmt.Errorf("could not get task instance stderr due to the following error : %v", err)
+		return "", fmt.Errorf("could not get task instance stderr due to the following error : %w", err)
 	}
 
 	var stderr string
@@ -610,9 +707,14 @@ func (c *Client) GetTaskInstanceStderr(uuid string, instanceId int) (string, err
 
 // Will get the previous stderr for a task on a specific instance
 func (c *Client) GetLastTaskInstanceStderr(uuid string, instanceId int) (string, error) {
-	data, _, err := c.sendRequest("POST", []byte{}, nil, fmt.Sprintf("tasks/%v/stderr/%v", uuid, instanceId))
+	return c.GetLastTaskInstanceStderrWithContext(context.Background(), uuid, instanceId)
+}
+
+// Same as `GetLastTaskInstanceStderr`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetLastTaskInstanceStderrWithContext(ctx context.Context, uuid string, instanceId int) (string, error) {
+	data, _, err := c.sendRequest(ctx, "POST", []byte{}, nil, fmt.Sprintf("tasks/%v/stderr/%v", uuid, instanceId))
 	if err != nil {
-		return "", fmt.Errorf("could not get last task instance stderr due to the following error : %v", err)
+		return "", fmt.Errorf("could not get last task instance stderr due to the following error : %w", err)
 	}
 
 	var stderr string
@@ -636,13 +738,18 @@ type CreateTaskSnapshotPayload struct {
 
 // Will create a periodic snapshot for a task using the UUID as string and a `CreateTaskSnapshotPayload` struct as arguments
 func (c *Client) CreateTaskPeriodicSnapshot(uuid string, payload *CreateTaskSnapshotPayload) error {
+	return c.CreateTaskPeriodicSnapshotWithContext(context.Background(), uuid, payload)
+}
+
+// Same as `CreateTaskPeriodicSnapshot`, but accepting a context to control cancellation and deadlines
+func (c *Client) CreateTaskPeriodicSnapshotWithContext(ctx context.Context, uuid string, payload *CreateTaskSnapshotPayload) error {
 	payloadJson, err := json.Marshal(&payload)
 	if err != nil {
 		return helpers.FormatJsonMarshalError(err)
 	}
 
-	if _, _, err = c.sendRequest("POST", payloadJson, nil, fmt.Sprintf("tasks/%v/snapshot/periodic", uuid)); err != nil {
-		return fmt.Errorf("could not create a task periodic snapshot due to the following error : %v", err)
+	if _, _, err = c.sendRequest(ctx, "POST", payloadJson, nil, fmt.Sprintf("tasks/%v/snapshot/periodic", uuid)); err != nil {
+		return fmt.Errorf("could not create a task periodic snapshot due to the following error : %w", err)
 	}
 
 	return nil
@@ -650,14 +757,19 @@ func (c *Client) CreateTaskPeriodicSnapshot(uuid string, payload *CreateTaskSnap
 
 // Will create a unique snapshot for a task using the UUID as string and a `CreateTaskSnapshotPayload` struct as arguments
 func (c *Client) CreateTaskUniqueSnapshot(uuid string, payload *CreateTaskSnapshotPayload) error {
+	return c.CreateTaskUniqueSnapshotWithContext(context.Background(), uuid, payload)
+}
+
+// Same as `CreateTaskUniqueSnapshot`, but accepting a context to control cancellation and deadlines
+func (c *Client) CreateTaskUniqueSnapshotWithContext(ctx context.Context, uuid string, payload *CreateTaskSnapshotPayload) error {
 	payloadJson, err := json.Marshal(&payload)
 	if err != nil {
 		return helpers.FormatJsonMarshalError(err)
 	}
 
-	_, _, err = c.sendRequest("POST", payloadJson, nil, fmt.Sprintf("tasks/%v/snapshot", uuid))
+	_, _, err = c.sendRequest(ctx, "POST", payloadJson, nil, fmt.Sprintf("tasks/%v/snapshot", uuid))
 	if err != nil {
-		return fmt.Errorf("could not create a task unique snapshot due to the following error : %v", err)
+		return fmt.Errorf("could not create a task unique snapshot due to the following error : %w", err)
 	}
 
 	return nil
@@ -705,6 +817,11 @@ type RetryTaskPayload struct {
 // Will retry a task using the UUID as string, and a `CreateTaskPayload` struct as arguments
 // Return a `UUIDResponse` containing the UUID of the newly retried task
 func (c *Client) RetryTask(uuid string, payload *RetryTaskPayload) (UUIDResponse, error) {
+	return c.RetryTaskWithContext(context.Background(), uuid, payload)
+}
+
+// Same as `RetryTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) RetryTaskWithContext(ctx context.Context, uuid string, payload *RetryTaskPayload) (UUIDResponse, error) {
 	var response UUIDResponse
 
 	payloadJson, err := json.Marshal(payload)
@@ -712,9 +829,9 @@ func (c *Client) RetryTask(uuid string, payload *RetryTaskPayload) (UUIDResponse
 		return response, helpers.FormatJsonMarshalError(err)
 	}
 
-	data, _, err := c.sendRequest("POST", payloadJson, nil, fmt.Sprintf("tasks/%v/retry", uuid))
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, fmt.Sprintf("tasks/%v/retry", uuid))
 	if err != nil {
-		return UUIDResponse{}, fmt.Errorf("could not retry task due to the following error : %v", err)
+		return UUIDResponse{}, fmt.Errorf("could not retry task due to the following error : %w", err)
 	}
 
 	err = json.Unmarshal(data, &response)
@@ -767,6 +884,11 @@ type RecoverTaskPayload struct {
 // Will recover a task using the UUID as string, and a `CreateTaskPayload` struct as arguments
 // Return a `UUIDResponse` containing the UUID of the newly recovered task
 func (c *Client) RecoverTask(uuid string, payload *RecoverTaskPayload) (UUIDResponse, error) {
+	return c.RecoverTaskWithContext(context.Background(), uuid, payload)
+}
+
+// Same as `RecoverTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) RecoverTaskWithContext(ctx context.Context, uuid string, payload *RecoverTaskPayload) (UUIDResponse, error) {
 	var response UUIDResponse
 
 	payloadJson, err := json.Marshal(&payload)
@@ -774,9 +896,9 @@ func (c *Client) RecoverTask(uuid string, payload *RecoverTaskPayload) (UUIDResp
 		return response, helpers.FormatJsonMarshalError(err)
 	}
 
-	data, _, err := c.sendRequest("POST", payloadJson, nil, fmt.Sprintf("tasks/%v/recover", uuid))
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, fmt.Sprintf("tasks/%v/recover", uuid))
 	if err != nil {
-		return UUIDResponse{}, fmt.Errorf("could not recover task due to the following error : %v", err)
+		return UUIDResponse{}, fmt.Errorf("could not recover task due to the following error : %w", err)
 	}
 
 	err = json.Unmarshal(data, &response)
@@ -828,6 +950,11 @@ type ResumeTaskPayload struct {
 // Will resume a task using the UUID as string, and a `CreateTaskPayload` struct as arguments
 // Return a `UUIDResponse` containing the UUID of the newly resumed task
 func (c *Client) ResumeTask(uuid string, payload *ResumeTaskPayload) (UUIDResponse, error) {
+	return c.ResumeTaskWithContext(context.Background(), uuid, payload)
+}
+
+// Same as `ResumeTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) ResumeTaskWithContext(ctx context.Context, uuid string, payload *ResumeTaskPayload) (UUIDResponse, error) {
 	var response UUIDResponse
 
 	payloadJson, err := json.Marshal(&payload)
@@ -835,9 +962,9 @@ func (c *Client) ResumeTask(uuid string, payload *ResumeTaskPayload) (UUIDRespon
 		return response, helpers.FormatJsonMarshalError(err)
 	}
 
-	data, _, err := c.sendRequest("POST", payloadJson, nil, fmt.Sprintf("tasks/%v/resume", uuid))
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, fmt.Sprintf("tasks/%v/resume", uuid))
 	if err != nil {
-		return UUIDResponse{}, fmt.Errorf("could not resume task due to the following error : %v", err)
+		return UUIDResponse{}, fmt.Errorf("could not resume task due to the following error : %w", err)
 	}
 
 	err = json.Unmarshal(data, &response)
@@ -890,6 +1017,11 @@ type CloneTaskPayload struct {
 // Will clone a task using the UUID as string, and a `CreateTaskPayload` struct as arguments
 // Return a `UUIDResponse` containing the UUID of the newly cloned task
 func (c *Client) CloneTask(uuid string, payload *CloneTaskPayload) (UUIDResponse, error) {
+	return c.CloneTaskWithContext(context.Background(), uuid, payload)
+}
+
+// Same as `CloneTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) CloneTaskWithContext(ctx context.Context, uuid string, payload *CloneTaskPayload) (UUIDResponse, error) {
 	var response UUIDResponse
 
 	payloadJson, err := json.Marshal(&payload)
@@ -897,9 +1029,9 @@ func (c *Client) CloneTask(uuid string, payload *CloneTaskPayload) (UUIDResponse
 		return response, helpers.FormatJsonMarshalError(err)
 	}
 
-	data, _, err := c.sendRequest("POST", payloadJson, nil, fmt.Sprintf("tasks/%v/clone", uuid))
+	data, _, err := c.sendRequest(ctx, "POST", payloadJson, nil, fmt.Sprintf("tasks/%v/clone", uuid))
 	if err != nil {
-		return UUIDResponse{}, fmt.Errorf("could not clone task due to the following error : %v", err)
+		return UUIDResponse{}, fmt.Errorf("could not clone task due to the following error : %w", err)
 	}
 
 	err = json.Unmarshal(data, &response)
@@ -917,14 +1049,19 @@ type UpdateTaskPayload struct {
 
 // Will update the fields of a task using the UUID as an argument, as well as a `UpdateTaskPayload` struct
 func (c *Client) UpdateTask(uuid string, payload UpdateTaskPayload) error {
+	return c.UpdateTaskWithContext(context.Background(), uuid, payload)
+}
+
+// Same as `UpdateTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) UpdateTaskWithContext(ctx context.Context, uuid string, payload UpdateTaskPayload) error {
 	payloadJson, err := json.Marshal(payload)
 	if err != nil {
 		return helpers.FormatJsonMarshalError(err)
 	}
 
-	_, _, err = c.sendRequest("PUT", payloadJson, nil, fmt.Sprintf("tasks/%v", uuid))
+	_, _, err = c.sendRequest(ctx, "PUT", payloadJson, nil, fmt.Sprintf("tasks/%v", uuid))
 	if err != nil {
-		return fmt.Errorf("could not update task due to the following error : %v", err)
+		return fmt.Errorf("could not update task due to the following error : %w", err)
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package qarnot
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -1892,3 +1893,57 @@ func TestCloneTask(t *testing.T) {
 		t.Errorf("found    : %v", err.Error())
 	}
 }
+
+func TestSearchTasks(t *testing.T) {
+	expected := `[
+		{"uuid": "e1d8e5fc-b28f-4ed8-9b72-7ea991d9cfc3", "name": "render-1", "state": "Success"}
+	]`
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if r.URL.Path == "/v1/tasks/search" && r.Method == "POST" && string(body) == `{"filter":{"operator":"Equal","field":"State","value":"Success"}}` {
+				fmt.Fprint(w, expected)
+			} else {
+				w.WriteHeader(400)
+				fmt.Fprint(w, `{"message": "Invalid filter"}`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	filter := Eq("State", "Success")
+	tasks, err := client.SearchTasks(SearchOptions{Filter: &filter})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	expectedData := []Task{{UUID: "e1d8e5fc-b28f-4ed8-9b72-7ea991d9cfc3", Name: "render-1", State: "Success"}}
+	if !reflect.DeepEqual(tasks, expectedData) {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedData)
+		t.Errorf("found    : %v", tasks)
+	}
+
+	filter = Eq("Unknown", 1)
+	_, err = client.SearchTasks(SearchOptions{Filter: &filter})
+	expectedErrorString := "could not search tasks due to the following error : [HTTP 400] Invalid filter"
+	if err.Error() != expectedErrorString {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedErrorString)
+		t.Errorf("found    : %v", err.Error())
+	}
+}
@@ -0,0 +1,671 @@
+package qarnot
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// Default size of the parts of a transfer
+	DefaultPartSize int64 = 8 * 1024 * 1024
+	// Smallest size allowed by S3 for the parts of a multipart upload, except for the last one
+	MinPartSize int64 = 5 * 1024 * 1024
+	// Default number of parts transferred at the same time
+	DefaultTransferConcurrency = 4
+	// Largest number of parts allowed by S3 for a multipart upload
+	MaxUploadParts int64 = 10000
+)
+
+// Struct representing the options of a `TransferManager`
+type TransferOptions struct {
+	// Size of the parts of a transfer, `DefaultPartSize` when 0, and at least `MinPartSize`
+	// Objects smaller than a part are transferred in a single request, and the parts of objects which
+	// would need more than `MaxUploadParts` parts are made larger
+	PartSize int64
+	// Number of parts transferred at the same time, `DefaultTransferConcurrency` when 0
+	Concurrency int
+	// Called every time a part was transferred, with the number of bytes transferred so far and the total size
+	// Calls are never concurrent
+	OnProgress func(transferred int64, total int64)
+	// Path of a local file in which the progress of a transfer is saved, so that it can be resumed
+	// after an interruption by transferring the same object again with the same state file
+	// The file is removed once the transfer is completed, and transfers cannot be resumed when empty
+	// The state of another transfer found in the file is discarded, aborting its multipart upload if any
+	StateFile string
+}
+
+// Transfer manager, uploading and downloading objects in multiple parts in parallel
+// Uploaded parts are checked using their MD5, as well as the whole object once completed
+// Downloaded objects are checked once completed using their ETag when it allows it : the MD5 of the whole
+// object, or for objects uploaded in multiple parts of the same size, the MD5 of each part (such objects are
+// then downloaded using the same parts)
+//
+//	manager := client.NewTransferManager(qarnot.TransferOptions{PartSize: 64 * 1024 * 1024, StateFile: "upload.state"})
+//	err := manager.Upload("my-bucket", "dataset.tar", "/data/dataset.tar")
+type TransferManager struct {
+	client  *Client
+	options TransferOptions
+	// Largest number of parts of a transfer, only lowered by tests
+	maxParts int64
+}
+
+// Will create a transfer manager using the given options
+func (c *Client) NewTransferManager(options TransferOptions) *TransferManager {
+	if options.PartSize == 0 {
+		options.PartSize = DefaultPartSize
+	}
+	options.PartSize = max(options.PartSize, MinPartSize)
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultTransferConcurrency
+	}
+	return &TransferManager{client: c, options: options, maxParts: MaxUploadParts}
+}
+
+// Will return the size of the parts used to transfer an object of the given size
+// Parts are made larger than the configured size when needed to stay under the maximum number of parts,
+// rounded up to a MiB
+func (m *TransferManager) partSize(size int64) int64 {
+	return multipartPartSize(size, m.options.PartSize, m.maxParts)
+}
+
+// Will return `partSize`, or the smallest size in MiB needed to split an object of the given size in at most `maxParts` parts
+func multipartPartSize(size int64, partSize int64, maxParts int64) int64 {
+	const mib = 1024 * 1024
+	if minimum := (size + maxParts - 1) / maxParts; minimum > partSize {
+		return (minimum + mib - 1) / mib * mib
+	}
+	return partSize
+}
+
+// Struct representing a part of a transfer which is completed
+type transferPart struct {
+	Number int32  `json:"number"`
+	ETag   string `json:"etag,omitempty"`
+}
+
+// Struct representing the progress of a transfer, saved in the state file
+type transferState struct {
+	Bucket   string         `json:"bucket"`
+	Key      string         `json:"key"`
+	Size     int64          `json:"size"`
+	PartSize int64          `json:"partSize"`
+	ModTime  time.Time      `json:"modTime,omitempty"`
+	UploadId string         `json:"uploadId,omitempty"`
+	ETag     string         `json:"etag,omitempty"`
+	Parts    []transferPart `json:"parts"`
+}
+
+// Will load the state of a previous transfer, returns nil when there is none
+func (m *TransferManager) loadState() (*transferState, error) {
+	if m.options.StateFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(m.options.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var state transferState
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state file %v : %w", m.options.StateFile, err)
+	}
+	return &state, nil
+}
+
+// Will save the state of a transfer, replacing the state file at once
+func (m *TransferManager) saveState(state *transferState) error {
+	if m.options.StateFile == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	temporary := m.options.StateFile + ".tmp"
+	err = os.WriteFile(temporary, data, 0o600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(temporary, m.options.StateFile)
+}
+
+// Will remove the state file, once a transfer is completed
+func (m *TransferManager) removeState() {
+	if m.options.StateFile != "" {
+		os.Remove(m.options.StateFile)
+	}
+}
+
+// Number of times the state file is saved during a transfer, at most
+// Saving it after every part would write a quadratic amount of data, as it lists every completed part
+const stateSaves = 100
+
+// Will run `transfer` on each part which is not completed yet, `Concurrency` parts at a time
+// Completed parts are added to the state, which is saved every few parts and once done
+func (m *TransferManager) transferParts(ctx context.Context, state *transferState, transfer func(ctx context.Context, number int32, offset int64, size int64) (string, error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	partsCount := int32((state.Size + state.PartSize - 1) / state.PartSize)
+	saveInterval := max(partsCount/stateSaves, 1)
+
+	var transferred int64
+	completed := map[int32]bool{}
+	for _, part := range state.Parts {
+		transferred += min(state.PartSize, state.Size-int64(part.Number-1)*state.PartSize)
+		completed[part.Number] = true
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		firstErr  error
+		unsaved   int32
+		semaphore = make(chan struct{}, m.options.Concurrency)
+	)
+	for number := int32(1); number <= partsCount; number++ {
+		if completed[number] {
+			continue
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			offset := int64(number-1) * state.PartSize
+			size := min(state.PartSize, state.Size-offset)
+			etag, err := transfer(ctx, number, offset, size)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				state.Parts = append(state.Parts, transferPart{Number: number, ETag: etag})
+				if unsaved++; unsaved >= saveInterval {
+					err = m.saveState(state)
+					unsaved = 0
+				}
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("part %v : %w", number, err)
+					cancel()
+				}
+				return
+			}
+
+			transferred += size
+			if m.options.OnProgress != nil {
+				m.options.OnProgress(transferred, state.Size)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Keep the parts completed since the last save, even when the transfer failed
+	if unsaved > 0 {
+		if err := m.saveState(state); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	slices.SortFunc(state.Parts, func(a, b transferPart) int { return int(a.Number - b.Number) })
+	return nil
+}
+
+// Will compute the MD5 of a part of a file
+func md5OfSection(file *os.File, offset int64, size int64) ([]byte, error) {
+	hash := md5.New()
+	_, err := io.Copy(hash, io.NewSectionReader(file, offset, size))
+	if err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// Will return an error if an ETag is not the MD5 of the transferred data
+// ETags which are not a plain MD5, such as the ones of encrypted objects, are not checked
+func checkETag(etag string, sum []byte) error {
+	etag = strings.Trim(etag, `"`)
+	if len(etag) != 32 || strings.Contains(etag, "-") {
+		return nil
+	}
+
+	if expected := hex.EncodeToString(sum); !strings.EqualFold(etag, expected) {
+		return fmt.Errorf("checksum mismatch, expected %v but the storage computed %v", expected, etag)
+	}
+	return nil
+}
+
+// Will return an error if the ETag of an object uploaded in multiple parts does not match the ETags of its parts
+// Such ETags are the MD5 of the MD5 of every part, followed by the number of parts
+func checkMultipartETag(etag string, parts []transferPart) error {
+	hash := md5.New()
+	for _, part := range parts {
+		sum, err := hex.DecodeString(strings.Trim(part.ETag, `"`))
+		if err != nil || len(sum) != md5.Size {
+			return nil
+		}
+		hash.Write(sum)
+	}
+
+	etag = strings.Trim(etag, `"`)
+	expected := fmt.Sprintf("%v-%v", hex.EncodeToString(hash.Sum(nil)), len(parts))
+	if strings.Contains(etag, "-") && !strings.EqualFold(etag, expected) {
+		return fmt.Errorf("checksum mismatch, expected %v but the storage computed %v", expected, etag)
+	}
+	return nil
+}
+
+// Will upload a local file to a bucket
+// Files larger than the part size are uploaded in multiple parts, in parallel
+// As with `PutObject`, the content type of the object is guessed from the extension of the key
+func (m *TransferManager) Upload(bucketName string, key string, localPath string) error {
+	return m.UploadWithContext(context.Background(), bucketName, key, localPath)
+}
+
+// Same as `Upload`, but accepting a context to control cancellation and deadlines
+func (m *TransferManager) UploadWithContext(ctx context.Context, bucketName string, key string, localPath string) error {
+	err := m.upload(ctx, bucketName, key, localPath)
+	if err != nil {
+		return fmt.Errorf("could not upload object to bucket due to the following error : %w", err)
+	}
+	return nil
+}
+
+func (m *TransferManager) upload(ctx context.Context, bucketName string, key string, localPath string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() <= m.options.PartSize {
+		sum, err := md5OfSection(file, 0, info.Size())
+		if err != nil {
+			return err
+		}
+
+		output, err := m.client.s3.PutObject(
+			ctx,
+			&s3.PutObjectInput{
+				Bucket:      &bucketName,
+				Key:         &key,
+				Body:        io.NewSectionReader(file, 0, info.Size()),
+				ContentMD5:  aws.String(base64.StdEncoding.EncodeToString(sum)),
+				ContentType: contentTypeOf(key),
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if err := checkETag(aws.ToString(output.ETag), sum); err != nil {
+			return err
+		}
+
+		if m.options.OnProgress != nil {
+			m.options.OnProgress(info.Size(), info.Size())
+		}
+		return nil
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+	if state != nil && !(state.Bucket == bucketName && state.Key == key && state.Size == info.Size() && state.ModTime.Equal(info.ModTime()) && state.UploadId != "") {
+		// The state belongs to another transfer, or the file changed since, so the upload starts over
+		m.discardState(state)
+		state = nil
+	}
+
+	if state != nil {
+		// Only keep the parts the storage still knows about
+		uploaded := map[int32]string{}
+		paginator := s3.NewListPartsPaginator(m.client.s3, &s3.ListPartsInput{
+			Bucket:   &bucketName,
+			Key:      &key,
+			UploadId: &state.UploadId,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			var noSuchUpload *types.NoSuchUpload
+			if errors.As(err, &noSuchUpload) {
+				state = nil
+				break
+			} else if err != nil {
+				return err
+			}
+			for _, part := range page.Parts {
+				uploaded[aws.ToInt32(part.PartNumber)] = aws.ToString(part.ETag)
+			}
+		}
+
+		if state != nil {
+			state.Parts = slices.DeleteFunc(state.Parts, func(part transferPart) bool {
+				return uploaded[part.Number] != part.ETag
+			})
+		}
+	}
+
+	if state == nil {
+		upload, err := m.client.s3.CreateMultipartUpload(
+			ctx,
+			&s3.CreateMultipartUploadInput{
+				Bucket:      &bucketName,
+				Key:         &key,
+				ContentType: contentTypeOf(key),
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		state = &transferState{
+			Bucket:   bucketName,
+			Key:      key,
+			Size:     info.Size(),
+			PartSize: m.partSize(info.Size()),
+			ModTime:  info.ModTime(),
+			UploadId: aws.ToString(upload.UploadId),
+			Parts:    []transferPart{},
+		}
+		err = m.saveState(state)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = m.transferParts(ctx, state, func(ctx context.Context, number int32, offset int64, size int64) (string, error) {
+		sum, err := md5OfSection(file, offset, size)
+		if err != nil {
+			return "", err
+		}
+
+		output, err := m.client.s3.UploadPart(
+			ctx,
+			&s3.UploadPartInput{
+				Bucket:     &bucketName,
+				Key:        &key,
+				UploadId:   &state.UploadId,
+				PartNumber: aws.Int32(number),
+				Body:       io.NewSectionReader(file, offset, size),
+				ContentMD5: aws.String(base64.StdEncoding.EncodeToString(sum)),
+			},
+		)
+		if err != nil {
+			return "", err
+		}
+
+		return aws.ToString(output.ETag), checkETag(aws.ToString(output.ETag), sum)
+	})
+	if err != nil {
+		m.abortUpload(state)
+		return err
+	}
+
+	completedParts := make([]types.CompletedPart, len(state.Parts))
+	for i, part := range state.Parts {
+		completedParts[i] = types.CompletedPart{PartNumber: aws.Int32(part.Number), ETag: aws.String(part.ETag)}
+	}
+
+	output, err := m.client.s3.CompleteMultipartUpload(
+		ctx,
+		&s3.CompleteMultipartUploadInput{
+			Bucket:          &bucketName,
+			Key:             &key,
+			UploadId:        &state.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completedParts},
+		},
+	)
+	if err != nil {
+		m.abortUpload(state)
+		return err
+	}
+
+	m.removeState()
+	return checkMultipartETag(aws.ToString(output.ETag), state.Parts)
+}
+
+// Will abort a multipart upload which cannot be resumed, so that its parts do not use storage anymore
+// Uploads are kept when a state file is given, so that they can be resumed later
+func (m *TransferManager) abortUpload(state *transferState) {
+	if m.options.StateFile == "" {
+		m.abortMultipartUpload(state)
+	}
+}
+
+// Will drop the state of another transfer found in the state file, aborting its multipart upload if any
+// as it cannot be resumed anymore
+func (m *TransferManager) discardState(state *transferState) {
+	if state.UploadId != "" {
+		m.abortMultipartUpload(state)
+	}
+	m.removeState()
+}
+
+func (m *TransferManager) abortMultipartUpload(state *transferState) {
+	m.client.s3.AbortMultipartUpload(
+		context.Background(),
+		&s3.AbortMultipartUploadInput{
+			Bucket:   &state.Bucket,
+			Key:      &state.Key,
+			UploadId: &state.UploadId,
+		},
+	)
+}
+
+// Will download an object from a bucket to a local file
+// Objects larger than the part size are downloaded in multiple parts, in parallel, using range requests
+// The object is first written to `localPath` followed by ".part", which is only renamed once complete
+func (m *TransferManager) Download(bucketName string, key string, localPath string) error {
+	return m.DownloadWithContext(context.Background(), bucketName, key, localPath)
+}
+
+// Same as `Download`, but accepting a context to control cancellation and deadlines
+func (m *TransferManager) DownloadWithContext(ctx context.Context, bucketName string, key string, localPath string) error {
+	err := m.download(ctx, bucketName, key, localPath)
+	if err != nil {
+		return fmt.Errorf("could not download object from bucket due to the following error : %w", err)
+	}
+	return nil
+}
+
+// Will return the size of the parts an object was uploaded with, so that it can be checked using its ETag
+// Returns 0 when it is unknown, or when the parts do not all have the same size (except the last one)
+func (m *TransferManager) uploadedPartSize(ctx context.Context, bucketName string, key string, etag string, size int64) int64 {
+	if !strings.Contains(etag, "-") {
+		return 0
+	}
+
+	head, err := m.client.s3.HeadObject(
+		ctx,
+		&s3.HeadObjectInput{
+			Bucket:     &bucketName,
+			Key:        &key,
+			PartNumber: aws.Int32(1),
+			IfMatch:    &etag,
+		},
+	)
+	if err != nil {
+		return 0
+	}
+
+	partSize := aws.ToInt64(head.ContentLength)
+	if partSize <= 0 || (size+partSize-1)/partSize != int64(aws.ToInt32(head.PartsCount)) {
+		return 0
+	}
+	return partSize
+}
+
+func (m *TransferManager) download(ctx context.Context, bucketName string, key string, localPath string) error {
+	head, err := m.client.s3.HeadObject(
+		ctx,
+		&s3.HeadObjectInput{
+			Bucket: &bucketName,
+			Key:    &key,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	size, etag := aws.ToInt64(head.ContentLength), aws.ToString(head.ETag)
+
+	if size <= m.options.PartSize {
+		_, err := m.client.downloadObject(ctx, bucketName, key, localPath)
+		if err == nil && m.options.OnProgress != nil {
+			m.options.OnProgress(size, size)
+		}
+		return err
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+	if state != nil && !(state.Bucket == bucketName && state.Key == key && state.UploadId == "") {
+		// The state belongs to another transfer, so the download starts over
+		m.discardState(state)
+		state = nil
+	}
+
+	partialPath := localPath + ".part"
+	if state != nil && (state.Size != size || state.ETag != etag) {
+		// The object changed since the previous download
+		state = nil
+	}
+	if state != nil {
+		if info, err := os.Stat(partialPath); err != nil || info.Size() != size {
+			state = nil
+		}
+	}
+	uploadedPartSize := m.uploadedPartSize(ctx, bucketName, key, etag, size)
+	if state == nil {
+		partSize := uploadedPartSize
+		if partSize == 0 {
+			partSize = m.partSize(size)
+		}
+		state = &transferState{Bucket: bucketName, Key: key, Size: size, PartSize: partSize, ETag: etag, Parts: []transferPart{}}
+		os.Remove(partialPath)
+	}
+
+	err = os.MkdirAll(filepath.Dir(localPath), 0o755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(partialPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = file.Truncate(size)
+	if err != nil {
+		return err
+	}
+
+	if m.options.StateFile == "" {
+		// The partial file is useless once the download failed, as it cannot be resumed
+		defer os.Remove(partialPath)
+	}
+
+	err = m.transferParts(ctx, state, func(ctx context.Context, number int32, offset int64, size int64) (string, error) {
+		object, err := m.client.s3.GetObject(
+			ctx,
+			&s3.GetObjectInput{
+				Bucket:  &bucketName,
+				Key:     &key,
+				Range:   aws.String(fmt.Sprintf("bytes=%v-%v", offset, offset+size-1)),
+				IfMatch: &etag,
+			},
+		)
+		if err != nil {
+			return "", err
+		}
+		defer object.Body.Close()
+
+		hash := md5.New()
+		written, err := io.Copy(io.MultiWriter(io.NewOffsetWriter(file, offset), hash), object.Body)
+		if err != nil {
+			return "", err
+		}
+		if written != size {
+			return "", fmt.Errorf("size mismatch, expected %v bytes but received %v", size, written)
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(etag, "-") {
+		sum, err := md5OfSection(file, 0, size)
+		if err == nil {
+			err = checkETag(etag, sum)
+		}
+		if err != nil {
+			m.removeState()
+			return err
+		}
+	} else if state.PartSize == uploadedPartSize {
+		if err := checkMultipartETag(etag, state.Parts); err != nil {
+			m.removeState()
+			return err
+		}
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(partialPath, localPath)
+	if err != nil {
+		return err
+	}
+
+	m.removeState()
+	return nil
+}
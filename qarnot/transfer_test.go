@@ -0,0 +1,271 @@
+package qarnot
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func transferTestData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i * 7 % 251)
+	}
+	return data
+}
+
+func TestTransferManagerUpload(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	data := transferTestData(int(2*MinPartSize + 1024))
+	localPath := filepath.Join(t.TempDir(), "dataset.bin")
+	os.WriteFile(localPath, data, 0o644)
+	stateFile := filepath.Join(t.TempDir(), "upload.state")
+
+	// Interrupt the upload on the second part
+	failed := false
+	storage.fail = func(r *http.Request) bool {
+		if r.URL.Query().Get("partNumber") == "2" && !failed {
+			failed = true
+			return true
+		}
+		return false
+	}
+
+	var progress []int64
+	manager := client.NewTransferManager(TransferOptions{
+		PartSize:    MinPartSize,
+		Concurrency: 1,
+		StateFile:   stateFile,
+		OnProgress: func(transferred int64, total int64) {
+			progress = append(progress, transferred)
+		},
+	})
+
+	err := manager.Upload("bucket", "dataset.bin", localPath)
+	if err == nil || !strings.Contains(err.Error(), "part 2 : ") {
+		t.Errorf("the upload should fail on the second part, found : %v", err)
+	}
+	if _, err := os.Stat(stateFile); err != nil {
+		t.Errorf("the state file should be kept to resume the upload : %v", err)
+	}
+
+	// Resume it, only uploading the remaining parts
+	putsBefore := storage.requestCount("PUT")
+	err = manager.Upload("bucket", "dataset.bin", localPath)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if puts := storage.requestCount("PUT") - putsBefore; puts != 2 {
+		t.Errorf("expected 2 parts to be uploaded when resuming, found %v", puts)
+	}
+
+	object, _ := storage.get("bucket", "dataset.bin")
+	if !bytes.Equal(object.data, data) || !strings.HasSuffix(object.etag, `-3"`) {
+		t.Errorf("the uploaded object is different from the local file (%v bytes, etag %v)", len(object.data), object.etag)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("the state file should be removed once the upload is completed : %v", err)
+	}
+
+	expectedProgress := []int64{MinPartSize, 2 * MinPartSize, 2*MinPartSize + 1024}
+	if len(progress) != 3 || progress[1] != expectedProgress[1] || progress[2] != expectedProgress[2] {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedProgress)
+		t.Errorf("found    : %v", progress)
+	}
+
+	// Small files are uploaded in a single request
+	smallPath := filepath.Join(t.TempDir(), "small.txt")
+	os.WriteFile(smallPath, []byte("small"), 0o644)
+	err = manager.Upload("bucket", "small.txt", smallPath)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if object, _ := storage.get("bucket", "small.txt"); string(object.data) != "small" || object.contentType != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected object : %+v", object)
+	}
+}
+
+func TestTransferManagerUploadPartLimit(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	data := transferTestData(int(4*MinPartSize + 1))
+	localPath := filepath.Join(t.TempDir(), "dataset.bin")
+	os.WriteFile(localPath, data, 0o644)
+	stateFile := filepath.Join(t.TempDir(), "upload.state")
+
+	storage.fail = func(r *http.Request) bool { return r.URL.Query().Get("partNumber") == "2" }
+
+	// 5 parts would be needed, so parts are made larger to only use 3 of them
+	manager := client.NewTransferManager(TransferOptions{PartSize: MinPartSize, Concurrency: 1, StateFile: stateFile})
+	manager.maxParts = 3
+	err := manager.Upload("bucket", "dataset.bin", localPath)
+	if err == nil {
+		t.Error("err should not be equal to nil")
+	}
+
+	state, err := manager.loadState()
+	if err != nil || state == nil || state.PartSize != 7*1024*1024 {
+		t.Fatalf("the state file should hold the part size of the upload, found %+v (%v)", state, err)
+	}
+
+	// Resumed uploads use the part size of the state file, whatever the options
+	storage.fail = nil
+	manager = client.NewTransferManager(TransferOptions{StateFile: stateFile})
+	err = manager.Upload("bucket", "dataset.bin", localPath)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+
+	object, _ := storage.get("bucket", "dataset.bin")
+	if !bytes.Equal(object.data, data) || !strings.HasSuffix(object.etag, `-3"`) {
+		t.Errorf("the uploaded object is different from the local file (%v bytes, etag %v)", len(object.data), object.etag)
+	}
+}
+
+func TestTransferManagerUploadStaleState(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	data := transferTestData(int(2*MinPartSize + 1024))
+	localPath := filepath.Join(t.TempDir(), "dataset.bin")
+	os.WriteFile(localPath, data, 0o644)
+	stateFile := filepath.Join(t.TempDir(), "upload.state")
+
+	storage.fail = func(r *http.Request) bool { return r.URL.Query().Get("partNumber") == "2" }
+
+	manager := client.NewTransferManager(TransferOptions{PartSize: MinPartSize, Concurrency: 1, StateFile: stateFile})
+	err := manager.Upload("bucket", "first.bin", localPath)
+	if err == nil {
+		t.Error("err should not be equal to nil")
+	}
+
+	// Another upload using the same state file aborts the previous one, and starts over
+	storage.fail = nil
+	err = manager.Upload("bucket", "second.bin", localPath)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if object, _ := storage.get("bucket", "second.bin"); !bytes.Equal(object.data, data) {
+		t.Errorf("the uploaded object is different from the local file (%v bytes)", len(object.data))
+	}
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+	if storage.hasUploads("bucket") {
+		t.Error("the upload of first.bin should be aborted")
+	}
+}
+
+func TestTransferManagerDownload(t *testing.T) {
+	storage := newFakeS3(t)
+	data := transferTestData(int(2*MinPartSize + 1024))
+	storage.put("bucket", "dataset.bin", string(data))
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	localPath := filepath.Join(t.TempDir(), "nested", "dataset.bin")
+	stateFile := filepath.Join(t.TempDir(), "download.state")
+
+	// Interrupt the download on the second part
+	failed := false
+	storage.fail = func(r *http.Request) bool {
+		if strings.HasPrefix(r.Header.Get("Range"), "bytes=5242880-") && !failed {
+			failed = true
+			return true
+		}
+		return false
+	}
+
+	manager := client.NewTransferManager(TransferOptions{PartSize: MinPartSize, Concurrency: 1, StateFile: stateFile})
+	err := manager.Download("bucket", "dataset.bin", localPath)
+	if err == nil || !strings.Contains(err.Error(), "part 2 : ") {
+		t.Errorf("the download should fail on the second part, found : %v", err)
+	}
+	if _, err := os.Stat(localPath); !os.IsNotExist(err) {
+		t.Errorf("the local file should not exist until the download is completed : %v", err)
+	}
+
+	// Resume it, only downloading the remaining parts
+	getsBefore := storage.requestCount("GET")
+	err = manager.Download("bucket", "dataset.bin", localPath)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if gets := storage.requestCount("GET") - getsBefore; gets != 2 {
+		t.Errorf("expected 2 parts to be downloaded when resuming, found %v", gets)
+	}
+
+	downloaded, err := os.ReadFile(localPath)
+	if err != nil || !bytes.Equal(downloaded, data) {
+		t.Errorf("the downloaded file is different from the object (%v bytes, %v)", len(downloaded), err)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("the state file should be removed once the download is completed : %v", err)
+	}
+
+	// Parts are made larger to stay under the maximum number of parts
+	manager = client.NewTransferManager(TransferOptions{PartSize: MinPartSize})
+	manager.maxParts = 2
+	getsBefore = storage.requestCount("GET")
+	err = manager.Download("bucket", "dataset.bin", localPath)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if gets := storage.requestCount("GET") - getsBefore; gets != 2 {
+		t.Errorf("expected 2 parts to be downloaded, found %v", gets)
+	}
+
+	// A corrupted object is detected using its ETag
+	storage.buckets["bucket"]["dataset.bin"] = fakeObject{data: data, etag: `"00000000000000000000000000000000"`}
+	err = manager.Download("bucket", "dataset.bin", localPath)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("the download should fail on the checksum, found : %v", err)
+	}
+}
+
+func TestTransferManagerDownloadMultipartObject(t *testing.T) {
+	storage := newFakeS3(t)
+	storage.put("bucket", "placeholder", "")
+	client := storage.client(t, "http://fake.api.qarnope.com")
+
+	data := transferTestData(int(2*MinPartSize + 1024))
+	uploadPath := filepath.Join(t.TempDir(), "dataset.bin")
+	os.WriteFile(uploadPath, data, 0o644)
+	err := client.NewTransferManager(TransferOptions{PartSize: MinPartSize}).Upload("bucket", "dataset.bin", uploadPath)
+	if err != nil {
+		t.Fatalf("could not upload the object: %v", err)
+	}
+
+	// The object is downloaded using the parts it was uploaded with, whatever the options, so that its ETag can be checked
+	localPath := filepath.Join(t.TempDir(), "dataset.bin")
+	manager := client.NewTransferManager(TransferOptions{PartSize: MinPartSize + 1024*1024})
+	getsBefore := storage.requestCount("GET")
+	err = manager.Download("bucket", "dataset.bin", localPath)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if gets := storage.requestCount("GET") - getsBefore; gets != 3 {
+		t.Errorf("expected the 3 parts of the upload to be downloaded, found %v", gets)
+	}
+	if downloaded, err := os.ReadFile(localPath); err != nil || !bytes.Equal(downloaded, data) {
+		t.Errorf("the downloaded file is different from the object (%v bytes, %v)", len(downloaded), err)
+	}
+
+	// A corrupted part is detected using the ETag of the object
+	object, _ := storage.get("bucket", "dataset.bin")
+	object.data = bytes.Clone(object.data)
+	object.data[len(object.data)-1]++
+	storage.store("bucket", "dataset.bin", object)
+	err = manager.Download("bucket", "dataset.bin", localPath)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("the download should fail on the checksum, found : %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package qarnot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -44,10 +45,15 @@ type UserInfo struct {
 }
 
 func (c *Client) GetUserInfo() (UserInfo, error) {
+	return c.GetUserInfoWithContext(context.Background())
+}
+
+// Same as `GetUserInfo`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetUserInfoWithContext(ctx context.Context) (UserInfo, error) {
 	// Send request and get back data
-	data, _, err := c.sendRequest("GET", []byte{}, make(map[string]string), "info")
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, make(map[string]string), "info")
 	if err != nil {
-		return UserInfo{}, fmt.Errorf("could not get user info due to the following error : %v", err)
+		return UserInfo{}, fmt.Errorf("could not get user info due to the following error : %w", err)
 	}
 
 	// Convert data to UserInfo struct
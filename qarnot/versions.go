@@ -1,6 +1,7 @@
 package qarnot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -8,9 +9,14 @@ import (
 )
 
 func (c *Client) GetVersions() ([]Version, error) {
-	data, _, err := c.sendRequest("GET", []byte{}, make(map[string]string), "versions")
+	return c.GetVersionsWithContext(context.Background())
+}
+
+// Same as `GetVersions`, but accepting a context to control cancellation and deadlines
+func (c *Client) GetVersionsWithContext(ctx context.Context) ([]Version, error) {
+	data, _, err := c.sendRequest(ctx, "GET", []byte{}, make(map[string]string), "versions")
 	if err != nil {
-		return []Version{}, fmt.Errorf("could not get versions due to the following error : %v", err)
+		return []Version{}, fmt.Errorf("could not get versions due to the following error : %w", err)
 	}
 
 	var versions []Version
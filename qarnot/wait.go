@@ -0,0 +1,123 @@
+package qarnot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Struct representing the options of `WaitForTask` and `WaitForJob`, typed by the state they wait for
+// (`WaitOptions[qarnot.TaskState]` or `WaitOptions[qarnot.JobState]`)
+type WaitOptions[S TaskState | JobState] struct {
+	// Also stop waiting when one of these states is reached (such as `qarnot.TaskFullyExecuting`)
+	UntilStates []S
+	// Stop waiting after this duration, no timeout when 0 (the context can still be used)
+	Timeout time.Duration
+	// The polling interval starts at `MinInterval`, and grows up to `MaxInterval` while nothing
+	// changes. It goes back to `MinInterval` as soon as the state or the progress changes
+	// 2 and 30 seconds by default
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// Called every time the state or the progress changes
+	// For tasks, the progress is the execution progress from 0 to 100. For jobs, it is always 0
+	OnProgress func(progress float64, state S)
+}
+
+func (o *WaitOptions[S]) withDefaults() WaitOptions[S] {
+	options := *o
+	if options.MinInterval <= 0 {
+		options.MinInterval = 2 * time.Second
+	}
+	if options.MaxInterval < options.MinInterval {
+		options.MaxInterval = max(30*time.Second, options.MinInterval)
+	}
+	return options
+}
+
+// Poll the state returned by `poll` until it is terminal or one of `WaitOptions.UntilStates`, following the given options
+func waitForState[S TaskState | JobState](ctx context.Context, options WaitOptions[S], poll func(context.Context) (state S, terminal bool, progress float64, err error)) error {
+	options = options.withDefaults()
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	interval := options.MinInterval
+	var lastState S
+	lastProgress := -1.0
+	for {
+		state, terminal, progress, err := poll(ctx)
+		if err != nil {
+			return err
+		}
+
+		if state != lastState || progress != lastProgress {
+			if options.OnProgress != nil {
+				options.OnProgress(progress, state)
+			}
+			lastState, lastProgress = state, progress
+			interval = options.MinInterval
+		} else {
+			interval = min(interval*3/2, options.MaxInterval)
+		}
+
+		if terminal || slices.Contains(options.UntilStates, state) {
+			return nil
+		}
+
+		if err := sleepWithContext(ctx, interval); err != nil {
+			return fmt.Errorf("stopped waiting while in state %v : %w", state, err)
+		}
+	}
+}
+
+// Will wait for a task to reach a terminal state (Success, Failure or Cancelled), or one of `WaitOptions.UntilStates`
+// Returns the task as it was when the state was reached, or the last known task on error
+func (c *Client) WaitForTask(uuid string, options WaitOptions[TaskState]) (Task, error) {
+	return c.WaitForTaskWithContext(context.Background(), uuid, options)
+}
+
+// Same as `WaitForTask`, but accepting a context to control cancellation and deadlines
+func (c *Client) WaitForTaskWithContext(ctx context.Context, uuid string, options WaitOptions[TaskState]) (Task, error) {
+	var task Task
+	err := waitForState(ctx, options, func(ctx context.Context) (TaskState, bool, float64, error) {
+		current, err := c.GetTaskInfoWithContext(ctx, uuid)
+		if err != nil {
+			return "", false, 0, err
+		}
+		task = current
+		return task.State, task.State.IsTerminal(), task.Status.ExecutionProgress, nil
+	})
+	if err != nil {
+		return task, fmt.Errorf("could not wait for task due to the following error : %w", err)
+	}
+
+	return task, nil
+}
+
+// Will wait for a job to reach a terminal state (Completed or Deleting), or one of `WaitOptions.UntilStates`
+// Returns the job as it was when the state was reached, or the last known job on error
+func (c *Client) WaitForJob(uuid string, options WaitOptions[JobState]) (Job, error) {
+	return c.WaitForJobWithContext(context.Background(), uuid, options)
+}
+
+// Same as `WaitForJob`, but accepting a context to control cancellation and deadlines
+func (c *Client) WaitForJobWithContext(ctx context.Context, uuid string, options WaitOptions[JobState]) (Job, error) {
+	var job Job
+	err := waitForState(ctx, options, func(ctx context.Context) (JobState, bool, float64, error) {
+		current, err := c.GetJobInfoWithContext(ctx, uuid)
+		if err != nil {
+			return "", false, 0, err
+		}
+		job = current
+		return job.State, job.State.IsTerminal(), 0, nil
+	})
+	if err != nil {
+		return job, fmt.Errorf("could not wait for job due to the following error : %w", err)
+	}
+
+	return job, nil
+}
@@ -0,0 +1,147 @@
+package qarnot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForTask(t *testing.T) {
+	states := []string{
+		`{"uuid": "oktask", "state": "Submitted", "status": {"executionProgress": 0}}`,
+		`{"uuid": "oktask", "state": "FullyExecuting", "status": {"executionProgress": 10}}`,
+		`{"uuid": "oktask", "state": "FullyExecuting", "status": {"executionProgress": 60}}`,
+		`{"uuid": "oktask", "state": "UploadingResults", "status": {"executionProgress": 100}}`,
+		`{"uuid": "oktask", "state": "Success", "status": {"executionProgress": 100}}`,
+	}
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/tasks/oktask" {
+				call := int(calls.Add(1)) - 1
+				fmt.Fprint(w, states[min(call, len(states)-1)])
+			} else if r.URL.Path == "/v1/tasks/stucktask" {
+				fmt.Fprint(w, `{"uuid": "stucktask", "state": "Submitted"}`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	var progresses []float64
+	var progressStates []TaskState
+	options := WaitOptions[TaskState]{
+		MinInterval: time.Millisecond,
+		MaxInterval: 5 * time.Millisecond,
+		OnProgress: func(progress float64, state TaskState) {
+			progresses = append(progresses, progress)
+			progressStates = append(progressStates, state)
+		},
+	}
+
+	// Stop on an intermediate state
+	task, err := client.WaitForTask("oktask", WaitOptions[TaskState]{UntilStates: []TaskState{TaskFullyExecuting}, MinInterval: time.Millisecond})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if task.State != "FullyExecuting" {
+		t.Errorf("expected : FullyExecuting")
+		t.Errorf("found    : %v", task.State)
+	}
+
+	// Then wait for the end of the task
+	task, err = client.WaitForTask("oktask", options)
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if task.State != "Success" {
+		t.Errorf("expected : Success")
+		t.Errorf("found    : %v", task.State)
+	}
+
+	expectedProgresses := []float64{60, 100, 100}
+	if fmt.Sprint(progresses) != fmt.Sprint(expectedProgresses) {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedProgresses)
+		t.Errorf("found    : %v", progresses)
+	}
+	expectedStates := []TaskState{TaskFullyExecuting, TaskUploadingResults, TaskSuccess}
+	if !slices.Equal(progressStates, expectedStates) {
+		t.Error("different values.")
+		t.Errorf("expected : %v", expectedStates)
+		t.Errorf("found    : %v", progressStates)
+	}
+
+	// Timeout on a task which never ends
+	options.Timeout = 20 * time.Millisecond
+	task, err = client.WaitForTask("stucktask", options)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err should wrap context.DeadlineExceeded, found : %v", err)
+	}
+	if task.State != "Submitted" {
+		t.Errorf("the last known task should be returned, found : %+v", task)
+	}
+}
+
+func TestWaitForJob(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/jobs/okjob" && calls.Add(1) < 3 {
+				fmt.Fprint(w, `{"uuid": "okjob", "state": "Active"}`)
+			} else if r.URL.Path == "/v1/jobs/okjob" {
+				fmt.Fprint(w, `{"uuid": "okjob", "state": "Completed"}`)
+			} else {
+				w.WriteHeader(404)
+				fmt.Fprint(w, `{"message": "No such job: test"}`)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	qarnotConfig := QarnotConfig{
+		ApiUrl:     srv.URL,
+		ApiKey:     "xxx",
+		Email:      "test@example.org",
+		Version:    "v1",
+		StorageUrl: "http://fake.storage.qarnope.com",
+	}
+
+	client, err := NewClient(&qarnotConfig)
+	if err != nil {
+		t.Errorf("could not create a new client: %v", err)
+	}
+
+	job, err := client.WaitForJob("okjob", WaitOptions[JobState]{MinInterval: time.Millisecond})
+	if err != nil {
+		t.Errorf("err should be equal to nil: %v", err)
+	}
+	if job.State != "Completed" || calls.Load() != 3 {
+		t.Errorf("expected : Completed after 3 calls")
+		t.Errorf("found    : %v after %v calls", job.State, calls.Load())
+	}
+
+	_, err = client.WaitForJob("test", WaitOptions[JobState]{MinInterval: time.Millisecond})
+	if !IsNotFound(err) {
+		t.Errorf("err should be a not found error, found : %v", err)
+	}
+}